@@ -0,0 +1,334 @@
+package mazenv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/unixpickle/essentials"
+)
+
+const binaryVersion = 1
+
+// maxBinaryCells is the largest number of cells in a maze
+// which can be encoded in binary.
+const maxBinaryCells = 1 << 31
+
+// maxBinaryMazeSize is the largest possible encoding of a
+// maze in the current version: a version byte, six
+// varints, and the packed walls.
+const maxBinaryMazeSize = 1 + 6*binary.MaxVarintLen64 + maxBinaryCells/8
+
+// binaryMagic starts every multi-maze binary file.
+var binaryMagic = []byte("MZNV")
+
+// MarshalBinary encodes the maze in a compact binary
+// format.
+//
+// The encoding is a version byte, followed by the rows,
+// columns, start, and end as unsigned varints, followed by
+// the walls packed eight to a byte in row-major order
+// (least significant bit first).
+//
+// The maze may have at most 2^31 cells.
+func (m *Maze) MarshalBinary() ([]byte, error) {
+	if m.Rows < 0 || m.Cols < 0 || len(m.Walls) != m.Rows*m.Cols {
+		return nil, errors.New("marshal maze: dimensions do not match walls")
+	} else if len(m.Walls) > maxBinaryCells {
+		return nil, errors.New("marshal maze: too many cells")
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(binaryVersion)
+	for _, x := range []int{m.Rows, m.Cols, m.Start.Row, m.Start.Col, m.End.Row, m.End.Col} {
+		if x < 0 {
+			return nil, errors.New("marshal maze: negative coordinate")
+		}
+		var num [binary.MaxVarintLen64]byte
+		buf.Write(num[:binary.PutUvarint(num[:], uint64(x))])
+	}
+	packed := make([]byte, (len(m.Walls)+7)/8)
+	for i, wall := range m.Walls {
+		if wall {
+			packed[i/8] |= 1 << uint(i%8)
+		}
+	}
+	buf.Write(packed)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a maze that was encoded with
+// MarshalBinary.
+func (m *Maze) UnmarshalBinary(data []byte) (err error) {
+	defer essentials.AddCtxTo("unmarshal maze", &err)
+	r := bytes.NewReader(data)
+	version, err := r.ReadByte()
+	if err != nil {
+		return io.ErrUnexpectedEOF
+	} else if version != binaryVersion {
+		return errors.New("unsupported version")
+	}
+	var nums [6]int
+	for i := range nums {
+		x, err := binary.ReadUvarint(r)
+		if err != nil {
+			return io.ErrUnexpectedEOF
+		} else if x > 1<<31 {
+			return errors.New("coordinate out of range")
+		}
+		nums[i] = int(x)
+	}
+	res := Maze{
+		Rows:  nums[0],
+		Cols:  nums[1],
+		Start: Position{Row: nums[2], Col: nums[3]},
+		End:   Position{Row: nums[4], Col: nums[5]},
+	}
+	numCells := res.Rows * res.Cols
+	if numCells > maxBinaryCells {
+		return errors.New("too many cells")
+	}
+	if r.Len() != (numCells+7)/8 {
+		return errors.New("incorrect wall data size")
+	}
+	packed := data[len(data)-r.Len():]
+	res.Walls = make([]bool, numCells)
+	for i := range res.Walls {
+		res.Walls[i] = packed[i/8]&(1<<uint(i%8)) != 0
+	}
+	*m = res
+	return nil
+}
+
+// BinaryWriter writes a file containing many mazes in the
+// binary format of Maze.MarshalBinary.
+//
+// The file starts with a header, followed by each maze
+// prefixed with its length as an unsigned varint.
+// The list of mazes is terminated by a zero length.
+// Close writes an index of maze offsets at the end of the
+// file, which BinaryIndex uses for random access.
+type BinaryWriter struct {
+	w       *bufio.Writer
+	offset  int64
+	offsets []int64
+}
+
+// NewBinaryWriter creates a BinaryWriter and writes the
+// file header to w.
+func NewBinaryWriter(w io.Writer) (*BinaryWriter, error) {
+	res := &BinaryWriter{w: bufio.NewWriter(w)}
+	if err := res.write(append(append([]byte{}, binaryMagic...), binaryVersion)); err != nil {
+		return nil, essentials.AddCtx("write binary header", err)
+	}
+	return res, nil
+}
+
+// Write appends a maze to the file.
+func (b *BinaryWriter) Write(m *Maze) (err error) {
+	defer essentials.AddCtxTo("write binary maze", &err)
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	b.offsets = append(b.offsets, b.offset)
+	if err := b.writeUvarint(uint64(len(data))); err != nil {
+		return err
+	}
+	return b.write(data)
+}
+
+// Close writes the index and flushes the file.
+//
+// It does not close the underlying io.Writer.
+func (b *BinaryWriter) Close() (err error) {
+	defer essentials.AddCtxTo("write binary index", &err)
+	if err := b.writeUvarint(0); err != nil {
+		return err
+	}
+	indexOffset := b.offset
+	if err := b.writeUvarint(uint64(len(b.offsets))); err != nil {
+		return err
+	}
+	for _, offset := range b.offsets {
+		if err := b.writeUvarint(uint64(offset)); err != nil {
+			return err
+		}
+	}
+	var footer [8]byte
+	binary.LittleEndian.PutUint64(footer[:], uint64(indexOffset))
+	if err := b.write(footer[:]); err != nil {
+		return err
+	}
+	return b.w.Flush()
+}
+
+func (b *BinaryWriter) writeUvarint(x uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	return b.write(buf[:binary.PutUvarint(buf[:], x)])
+}
+
+func (b *BinaryWriter) write(data []byte) error {
+	n, err := b.w.Write(data)
+	b.offset += int64(n)
+	return err
+}
+
+// BinaryReader reads the mazes from a file created by a
+// BinaryWriter in order.
+type BinaryReader struct {
+	r    *bufio.Reader
+	done bool
+}
+
+// NewBinaryReader creates a BinaryReader and reads the
+// file header from r.
+func NewBinaryReader(r io.Reader) (*BinaryReader, error) {
+	res := &BinaryReader{r: bufio.NewReader(r)}
+	header := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(res.r, header); err != nil {
+		return nil, essentials.AddCtx("read binary header", err)
+	}
+	if err := checkBinaryHeader(header); err != nil {
+		return nil, essentials.AddCtx("read binary header", err)
+	}
+	return res, nil
+}
+
+// Read reads the next maze.
+//
+// After the last maze, io.EOF is returned.
+func (b *BinaryReader) Read() (maze *Maze, err error) {
+	if b.done {
+		return nil, io.EOF
+	}
+	size, err := binary.ReadUvarint(b.r)
+	if err != nil {
+		return nil, essentials.AddCtx("read binary maze", io.ErrUnexpectedEOF)
+	} else if size == 0 {
+		b.done = true
+		return nil, io.EOF
+	}
+	defer essentials.AddCtxTo("read binary maze", &err)
+	if size > maxBinaryMazeSize {
+		return nil, errors.New("maze size too large")
+	}
+	// Read into a growing buffer so that a corrupt size
+	// does not allocate memory for data that isn't there.
+	var data bytes.Buffer
+	if n, err := data.ReadFrom(io.LimitReader(b.r, int64(size))); err != nil {
+		return nil, err
+	} else if uint64(n) != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	maze = &Maze{}
+	if err := maze.UnmarshalBinary(data.Bytes()); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// BinaryIndex provides random access to the mazes in a
+// file created by a BinaryWriter.
+type BinaryIndex struct {
+	r       io.ReaderAt
+	offsets []int64
+
+	// ends stores the offset after the end of each maze.
+	ends []int64
+}
+
+// NewBinaryIndex reads the header and index of a file of
+// the given size.
+func NewBinaryIndex(r io.ReaderAt, size int64) (index *BinaryIndex, err error) {
+	defer essentials.AddCtxTo("read binary index", &err)
+	header := make([]byte, len(binaryMagic)+1)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if err := checkBinaryHeader(header); err != nil {
+		return nil, err
+	}
+	var footer [8]byte
+	if size < int64(len(header)+len(footer)) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if _, err := r.ReadAt(footer[:], size-int64(len(footer))); err != nil {
+		return nil, err
+	}
+	indexOffset := int64(binary.LittleEndian.Uint64(footer[:]))
+	if indexOffset < int64(len(header)) || indexOffset > size-int64(len(footer)) {
+		return nil, errors.New("index offset out of range")
+	}
+	br := bufio.NewReader(io.NewSectionReader(r, indexOffset,
+		size-int64(len(footer))-indexOffset))
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	} else if count > uint64(size) {
+		return nil, errors.New("invalid maze count")
+	}
+	index = &BinaryIndex{r: r, offsets: make([]int64, count), ends: make([]int64, count)}
+	for i := range index.offsets {
+		offset, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		} else if offset < uint64(len(header)) || offset >= uint64(indexOffset) ||
+			(i > 0 && int64(offset) <= index.offsets[i-1]) {
+			return nil, errors.New("maze offset out of range")
+		}
+		index.offsets[i] = int64(offset)
+		if i > 0 {
+			index.ends[i-1] = int64(offset)
+		}
+	}
+	if count > 0 {
+		// The last maze is followed by a zero terminator.
+		index.ends[count-1] = indexOffset - 1
+	}
+	return index, nil
+}
+
+// Len returns the number of mazes in the file.
+func (b *BinaryIndex) Len() int {
+	return len(b.offsets)
+}
+
+// Maze reads the maze at the given index.
+func (b *BinaryIndex) Maze(i int) (maze *Maze, err error) {
+	defer essentials.AddCtxTo("read binary maze", &err)
+	if i < 0 || i >= len(b.offsets) {
+		return nil, errors.New("index out of range")
+	}
+	var sizeBuf [binary.MaxVarintLen64]byte
+	n, err := b.r.ReadAt(sizeBuf[:], b.offsets[i])
+	if n == 0 {
+		return nil, err
+	}
+	size, sizeLen := binary.Uvarint(sizeBuf[:n])
+	if sizeLen <= 0 || size == 0 {
+		return nil, errors.New("invalid maze size")
+	} else if avail := b.ends[i] - b.offsets[i] - int64(sizeLen); avail < 0 ||
+		size > uint64(avail) {
+		return nil, errors.New("maze size too large")
+	}
+	data := make([]byte, size)
+	if _, err := b.r.ReadAt(data, b.offsets[i]+int64(sizeLen)); err != nil {
+		return nil, err
+	}
+	maze = &Maze{}
+	if err := maze.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+func checkBinaryHeader(header []byte) error {
+	if !bytes.Equal(header[:len(binaryMagic)], binaryMagic) {
+		return errors.New("not a binary maze file")
+	} else if header[len(binaryMagic)] != binaryVersion {
+		return errors.New("unsupported version")
+	}
+	return nil
+}
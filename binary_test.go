@@ -0,0 +1,157 @@
+package mazenv
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestMazeBinary(t *testing.T) {
+	for _, s := range []string{
+		testingMaze().String(),
+		"Ax",
+		"A\nw\nw\nw\nw\nw\nw\nw\nx",
+		"w.w.w.w.wA\n.w.w.w.w.w\nx.........",
+	} {
+		expected, err := ParseMaze(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := expected.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual Maze
+		if err := actual.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if actual.String() != s {
+			t.Errorf("expected %#v but got %#v", s, actual.String())
+		}
+		if err := actual.UnmarshalBinary(data[:len(data)-1]); err == nil {
+			t.Errorf("expected failure for truncated %#v", s)
+		}
+	}
+}
+
+func TestBinaryFile(t *testing.T) {
+	var mazes []*Maze
+	for i := int64(0); i < 10; i++ {
+		maze, err := GenerateSeed(&PrimGenerator{}, i, 21, 21)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mazes = append(mazes, maze)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewBinaryWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mazes {
+		if err := w.Write(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewBinaryReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range mazes {
+		actual, err := r.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual.String() != expected.String() {
+			t.Errorf("maze %d: expected %#v but got %#v", i, expected.String(),
+				actual.String())
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("expected EOF but got %v", err)
+	}
+
+	index, err := NewBinaryIndex(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Len() != len(mazes) {
+		t.Fatalf("expected %d mazes but got %d", len(mazes), index.Len())
+	}
+	for _, i := range []int{7, 0, 9, 3} {
+		actual, err := index.Maze(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual.String() != mazes[i].String() {
+			t.Errorf("maze %d: expected %#v but got %#v", i, mazes[i].String(),
+				actual.String())
+		}
+	}
+	if _, err := index.Maze(len(mazes)); err == nil {
+		t.Error("expected error for out of range index")
+	}
+}
+
+func TestBinaryCorrupt(t *testing.T) {
+	if _, err := (&Maze{Rows: 2, Cols: 2, Walls: make([]bool, 3)}).MarshalBinary(); err == nil {
+		t.Error("expected error for mismatched walls")
+	}
+
+	var buf bytes.Buffer
+	w, err := NewBinaryWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := w.Write(testingMaze()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the size of the first maze with a huge
+	// varint of the same length.
+	data := append([]byte{}, buf.Bytes()...)
+	sizeOffset := len(binaryMagic) + 1
+	if data[sizeOffset] >= 0x80 {
+		t.Fatal("unexpected multi-byte size")
+	}
+	corrupt := append(append(append([]byte{}, data[:sizeOffset]...),
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f), data[sizeOffset+1:]...)
+	r, err := NewBinaryReader(bytes.NewReader(corrupt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(); err == nil {
+		t.Error("expected error for huge size in stream")
+	}
+
+	// A size which is too large but still plausible must
+	// not read past the end of the maze.
+	data[sizeOffset] += 3
+	r, err = NewBinaryReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(); err == nil {
+		t.Error("expected error for wrong size in stream")
+	}
+	index, err := NewBinaryIndex(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.Maze(0); err == nil {
+		t.Error("expected error for wrong size in index")
+	}
+	if _, err := index.Maze(1); err != nil {
+		t.Errorf("unexpected error for intact maze: %v", err)
+	}
+}
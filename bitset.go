@@ -0,0 +1,56 @@
+package mazenv
+
+import "math/bits"
+
+// A Bitset is a fixed-size list of bits, packed 64 to a
+// word.
+//
+// Bit i is stored in word i/64 at bit i%64.
+type Bitset []uint64
+
+// NewBitset creates a Bitset with room for n bits, all of
+// which are zero.
+func NewBitset(n int) Bitset {
+	return make(Bitset, (n+63)/64)
+}
+
+// Get checks if bit i is set.
+func (b Bitset) Get(i int) bool {
+	return b[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Set sets bit i to the value.
+func (b Bitset) Set(i int, value bool) {
+	if value {
+		b[i/64] |= 1 << uint(i%64)
+	} else {
+		b[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+// Count counts the set bits.
+func (b Bitset) Count() int {
+	var res int
+	for _, word := range b {
+		res += bits.OnesCount64(word)
+	}
+	return res
+}
+
+// CountRange counts the set bits in the range [start, end).
+func (b Bitset) CountRange(start, end int) int {
+	if start >= end {
+		return 0
+	}
+	startWord, endWord := start/64, (end-1)/64
+	startMask := ^uint64(0) << uint(start%64)
+	endMask := ^uint64(0) >> uint(63-(end-1)%64)
+	if startWord == endWord {
+		return bits.OnesCount64(b[startWord] & startMask & endMask)
+	}
+	res := bits.OnesCount64(b[startWord]&startMask) + bits.OnesCount64(b[endWord]&endMask)
+	for _, word := range b[startWord+1 : endWord] {
+		res += bits.OnesCount64(word)
+	}
+	return res
+}
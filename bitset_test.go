@@ -0,0 +1,43 @@
+package mazenv
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBitset(t *testing.T) {
+	const size = 300
+	b := NewBitset(size)
+	expected := make([]bool, size)
+	for i := 0; i < 500; i++ {
+		idx, value := rand.Intn(size), rand.Intn(2) == 0
+		b.Set(idx, value)
+		expected[idx] = value
+	}
+	var total int
+	for i, value := range expected {
+		if b.Get(i) != value {
+			t.Fatalf("bit %d: expected %v", i, value)
+		}
+		if value {
+			total++
+		}
+	}
+	if b.Count() != total {
+		t.Errorf("expected count %d but got %d", total, b.Count())
+	}
+
+	for i := 0; i < 1000; i++ {
+		start := rand.Intn(size + 1)
+		end := start + rand.Intn(size+1-start)
+		var count int
+		for _, value := range expected[start:end] {
+			if value {
+				count++
+			}
+		}
+		if actual := b.CountRange(start, end); actual != count {
+			t.Fatalf("range [%d, %d): expected %d but got %d", start, end, count, actual)
+		}
+	}
+}
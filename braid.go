@@ -0,0 +1,85 @@
+package mazenv
+
+import "math/rand"
+
+// Braid removes dead ends from a maze by opening walls,
+// creating loops in the process.
+//
+// The fraction argument specifies the fraction of dead
+// ends to remove, from 0 (none) to 1 (all that can be).
+// Where possible, a dead end is removed by opening a wall
+// between it and a space on the opposite side of the wall,
+// preferring spaces which are dead ends themselves.
+//
+// If rng is nil, the global source from math/rand is used.
+func Braid(m *Maze, fraction float64, rng *rand.Rand) {
+	rng = randOrGlobal(rng)
+	ends := deadEnds(m)
+	rng.Shuffle(len(ends), func(i, j int) {
+		ends[i], ends[j] = ends[j], ends[i]
+	})
+
+	target := int(fraction*float64(len(ends)) + 0.5)
+	var removed int
+	for _, p := range ends {
+		if removed >= target {
+			break
+		}
+		if len(neighboringSpaces(m, p)) != 1 {
+			// Already removed by a previous opening.
+			continue
+		}
+		wall, ok := braidWall(m, p, rng)
+		if !ok {
+			continue
+		}
+		for _, n := range neighboringSpaces(m, wall) {
+			if n != p && len(neighboringSpaces(m, n)) == 1 {
+				removed++
+			}
+		}
+		m.Walls[m.CellIndex(wall)] = false
+		removed++
+	}
+}
+
+// braidWall chooses a wall next to a dead end which can
+// be opened to connect it to another space.
+func braidWall(m *Maze, deadEnd Position, rng *rand.Rand) (Position, bool) {
+	var straight, deadEndStraight, other []Position
+	for _, wall := range neighbors(m, deadEnd) {
+		if !m.Wall(wall) {
+			continue
+		}
+		beyond := Position{
+			Row: 2*wall.Row - deadEnd.Row,
+			Col: 2*wall.Col - deadEnd.Col,
+		}
+		if !m.Wall(beyond) {
+			straight = append(straight, wall)
+			if len(neighboringSpaces(m, beyond)) == 1 {
+				deadEndStraight = append(deadEndStraight, wall)
+			}
+		} else if len(neighboringSpaces(m, wall)) > 1 {
+			other = append(other, wall)
+		}
+	}
+	for _, options := range [][]Position{deadEndStraight, straight, other} {
+		if len(options) > 0 {
+			return options[rng.Intn(len(options))], true
+		}
+	}
+	return Position{}, false
+}
+
+// deadEnds returns all the spaces in the maze with
+// exactly one neighboring space.
+func deadEnds(m *Maze) []Position {
+	var res []Position
+	for _, p := range m.Positions() {
+		if !m.Wall(p) && len(neighboringSpaces(m, p)) == 1 {
+			res = append(res, p)
+		}
+	}
+	return res
+}
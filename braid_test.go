@@ -0,0 +1,32 @@
+package mazenv
+
+import "testing"
+
+func TestBraid(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		m, err := (&BacktrackerGenerator{}).Generate(21, 15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := m.String()
+		numDeadEnds := len(deadEnds(m))
+
+		Braid(m, 0, nil)
+		if m.String() != original {
+			t.Error("braiding with fraction 0 modified the maze")
+		}
+
+		Braid(m, 0.5, nil)
+		if n := len(deadEnds(m)); n > (numDeadEnds+1)/2 {
+			t.Errorf("expected at most %d dead ends but got %d", (numDeadEnds+1)/2, n)
+		}
+
+		Braid(m, 1, nil)
+		if n := len(deadEnds(m)); n != 0 {
+			t.Errorf("expected no dead ends but got %d: %#v", n, m.String())
+		}
+		if Solve(m) == nil {
+			t.Errorf("unsolvable: %#v", m.String())
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package mazenv
+
+import (
+	"flag"
+	"math/rand"
+)
+
+// CaveGenerator generates organic, cave-like maps using a
+// cellular automaton.
+//
+// The grid is first filled with random walls.
+// Then, a number of steps are performed in which spaces
+// with enough neighboring walls become walls (birth), and
+// walls with too few neighboring walls become spaces
+// (survival).
+// Neighbors include diagonals, and cells outside the grid
+// count as walls.
+//
+// Afterwards, disconnected regions are either removed or
+// joined with tunnels, so every maze is solvable.
+type CaveGenerator struct {
+	// FillProbability is the probability that each cell
+	// starts out as a wall.
+	// The value may range from 0 to 1.
+	//
+	// If 0, a default of 0.45 is used.
+	FillProbability float64
+
+	// BirthLimit is the number of neighboring walls at
+	// which a space becomes a wall.
+	//
+	// If 0, a default of 5 is used.
+	BirthLimit int
+
+	// SurvivalLimit is the number of neighboring walls
+	// needed for a wall to remain a wall.
+	//
+	// If 0, a default of 4 is used.
+	SurvivalLimit int
+
+	// Iterations is the number of automaton steps.
+	//
+	// If 0, a default of 4 is used.
+	Iterations int
+
+	// ConnectAll, if true, joins all the regions of the
+	// cave with tunnels.
+	// Otherwise, only the largest region is kept.
+	ConnectAll bool
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (c *CaveGenerator) Description() string {
+	return "cellular-automaton caves"
+}
+
+// AddFlags adds the generator's options as flags.
+func (c *CaveGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&c.FillProbability, "fill", 0.45, "initial probability of a wall")
+	fs.IntVar(&c.BirthLimit, "birth", 5, "neighboring walls needed to create a wall")
+	fs.IntVar(&c.SurvivalLimit, "survival", 4, "neighboring walls needed to keep a wall")
+	fs.IntVar(&c.Iterations, "iterations", 4, "number of automaton steps")
+	fs.BoolVar(&c.ConnectAll, "connect", false,
+		"join all regions with tunnels instead of keeping the largest")
+}
+
+// Generate generates a random cave.
+func (c *CaveGenerator) Generate(rows, cols int) (*Maze, error) {
+	return c.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (c *CaveGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	fillProb, birth, survival, iters := c.adjustedParams()
+
+	maze := &Maze{
+		Rows:  rows,
+		Cols:  cols,
+		Walls: make([]bool, rows*cols),
+	}
+	for i := range maze.Walls {
+		maze.Walls[i] = rng.Float64() < fillProb
+	}
+
+	next := make([]bool, len(maze.Walls))
+	for i := 0; i < iters; i++ {
+		for j, p := range maze.Positions() {
+			count := surroundingWalls(maze, p)
+			if maze.Walls[j] {
+				next[j] = count >= survival
+			} else {
+				next[j] = count >= birth
+			}
+		}
+		maze.Walls, next = next, maze.Walls
+	}
+
+	if c.ConnectAll {
+		connectRegions(maze, nil)
+	} else {
+		keepLargestRegion(maze)
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+func (c *CaveGenerator) adjustedParams() (fillProb float64, birth, survival, iters int) {
+	fillProb = c.FillProbability
+	if fillProb == 0 {
+		fillProb = 0.45
+	}
+	birth = c.BirthLimit
+	if birth == 0 {
+		birth = 5
+	}
+	survival = c.SurvivalLimit
+	if survival == 0 {
+		survival = 4
+	}
+	iters = c.Iterations
+	if iters == 0 {
+		iters = 4
+	}
+	return
+}
+
+// surroundingWalls counts the walls in the eight cells
+// around a position, including cells out of bounds.
+func surroundingWalls(m *Maze, p Position) int {
+	var count int
+	for row := p.Row - 1; row <= p.Row+1; row++ {
+		for col := p.Col - 1; col <= p.Col+1; col++ {
+			n := Position{Row: row, Col: col}
+			if n != p && m.Wall(n) {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,18 @@
+package mazenv
+
+import "testing"
+
+func TestCaveGeneratorConnected(t *testing.T) {
+	for _, connect := range []bool{false, true} {
+		gen := &CaveGenerator{ConnectAll: connect, FillProbability: 0.5}
+		for i := 0; i < 5; i++ {
+			m, err := gen.Generate(30, 40)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := len(spaceRegions(m)); n != 1 {
+				t.Errorf("connect=%v: expected 1 region but got %d", connect, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package mazenv
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// DifficultyStats describes features of a maze which make
+// it harder to solve.
+type DifficultyStats struct {
+	// SolutionLength is the number of steps in the shortest
+	// solution, or -1 if the maze is unsolvable.
+	SolutionLength int
+
+	// DeadEnds is the number of spaces with exactly one
+	// neighboring space.
+	DeadEnds int
+
+	// Decisions is the number of positions along the
+	// shortest solution (excluding the end) where there is
+	// more than one way forward.
+	Decisions int
+
+	// Score combines the other statistics into a number
+	// from 0 (easiest) to 1 (hardest).
+	//
+	// It is the mean of three ratios: the solution length
+	// to the distance of the farthest cell from the start,
+	// the decisions to the solution length, and the dead
+	// ends to the dead ends plus the solution length.
+	// Unsolvable mazes have a score of 1, and mazes where
+	// the start is the end have a score of 0.
+	Score float64
+}
+
+// MeasureDifficulty computes the difficulty of a maze.
+func MeasureDifficulty(m *Maze) DifficultyStats {
+	res := DifficultyStats{DeadEnds: len(deadEnds(m))}
+	solution := Solve(m)
+	if solution == nil {
+		res.SolutionLength = -1
+		res.Score = 1
+		return res
+	}
+	res.SolutionLength = len(solution) - 1
+	if res.SolutionLength == 0 {
+		return res
+	}
+	for i, p := range solution[:len(solution)-1] {
+		forward := len(neighboringSpaces(m, p))
+		if i > 0 {
+			forward--
+		}
+		if forward > 1 {
+			res.Decisions++
+		}
+	}
+
+	var maxDist int
+	for _, d := range distances(m, m.Start) {
+		if d > maxDist {
+			maxDist = d
+		}
+	}
+	length := float64(res.SolutionLength)
+	res.Score = (length/float64(maxDist) +
+		float64(res.Decisions)/length +
+		float64(res.DeadEnds)/(float64(res.DeadEnds)+length)) / 3
+	return res
+}
+
+// CurriculumGenerator generates mazes of a target
+// difficulty, as measured by MeasureDifficulty.
+//
+// The difficulty can be adjusted automatically during
+// training by reporting success rates to Update.
+//
+// It is safe to call methods on a CurriculumGenerator
+// from multiple Goroutines, but Difficulty should only be
+// modified directly before the generator is used.
+type CurriculumGenerator struct {
+	// Generators are the underlying generators.
+	// One is chosen at random for each candidate maze.
+	//
+	// If empty, a PrimGenerator is used.
+	Generators []Generator
+
+	// Difficulty is the target difficulty, from 0 to 1.
+	// Generation fails for values outside this range.
+	Difficulty float64
+
+	// Samples is the number of candidate mazes to
+	// generate, out of which the maze with the closest
+	// difficulty is chosen.
+	//
+	// If 0, a default of 10 is used.
+	Samples int
+
+	// Step is the amount by which Update changes the
+	// difficulty.
+	//
+	// If 0, a default of 0.05 is used.
+	Step float64
+
+	// MinSuccess and MaxSuccess bound the desired success
+	// rate.
+	// Update decreases the difficulty below MinSuccess and
+	// increases it above MaxSuccess.
+	//
+	// If 0, defaults of 0.5 and 0.8 are used.
+	MinSuccess float64
+	MaxSuccess float64
+
+	lock sync.Mutex
+}
+
+// Generate generates a maze of the current difficulty.
+func (c *CurriculumGenerator) Generate(rows, cols int) (*Maze, error) {
+	return c.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+// The underlying generators only use rng if they
+// implement RandGenerator.
+//
+// If rng is nil, the global source from math/rand is used.
+func (c *CurriculumGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	difficulty := c.CurrentDifficulty()
+	if difficulty < 0 || difficulty > 1 {
+		return nil, errors.New("generate: difficulty must be between 0 and 1")
+	}
+	gens := c.Generators
+	if len(gens) == 0 {
+		gens = []Generator{&PrimGenerator{}}
+	}
+	samples := c.Samples
+	if samples == 0 {
+		samples = 10
+	}
+
+	var best *Maze
+	var bestError float64
+	for i := 0; i < samples; i++ {
+		maze, err := generateRand(gens[rng.Intn(len(gens))], rng, rows, cols)
+		if err != nil {
+			return nil, err
+		}
+
+		// Easier mazes have fewer dead ends and ends that
+		// are closer to the start.
+		Braid(maze, 1-difficulty, rng)
+		if !placeEndAtPercentile(rng, maze, difficulty) {
+			continue
+		}
+
+		score := MeasureDifficulty(maze).Score
+		if best == nil || math.Abs(score-difficulty) < bestError {
+			best = maze
+			bestError = math.Abs(score - difficulty)
+		}
+	}
+	if best == nil {
+		return nil, errors.New("generate: no solvable candidates")
+	}
+	return best, nil
+}
+
+// CurrentDifficulty returns the target difficulty.
+func (c *CurriculumGenerator) CurrentDifficulty() float64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.Difficulty
+}
+
+// Update adjusts the difficulty based on the fraction of
+// recent mazes that an agent solved.
+func (c *CurriculumGenerator) Update(successRate float64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	step, minSuccess, maxSuccess := c.Step, c.MinSuccess, c.MaxSuccess
+	if step == 0 {
+		step = 0.05
+	}
+	if minSuccess == 0 {
+		minSuccess = 0.5
+	}
+	if maxSuccess == 0 {
+		maxSuccess = 0.8
+	}
+	if successRate > maxSuccess {
+		c.Difficulty = math.Min(1, c.Difficulty+step)
+	} else if successRate < minSuccess {
+		c.Difficulty = math.Max(0, c.Difficulty-step)
+	}
+}
+
+// placeEndAtPercentile picks a random start and places the
+// end at a percentile of the distances from the start to
+// the other reachable cells.
+// It returns false if there are no other reachable cells.
+func placeEndAtPercentile(rng *rand.Rand, m *Maze, percentile float64) bool {
+	spaces := shuffledSpaces(rng, m)
+	if len(spaces) < 2 {
+		return false
+	}
+	start := spaces[0]
+	dists := distances(m, start)
+	var ends []Position
+	for _, p := range spaces[1:] {
+		if dists[m.CellIndex(p)] > 0 {
+			ends = append(ends, p)
+		}
+	}
+	if len(ends) == 0 {
+		return false
+	}
+	sort.SliceStable(ends, func(i, j int) bool {
+		return dists[m.CellIndex(ends[i])] < dists[m.CellIndex(ends[j])]
+	})
+	m.Start = start
+	m.End = ends[int(percentile*float64(len(ends)-1)+0.5)]
+	return true
+}
@@ -0,0 +1,89 @@
+package mazenv
+
+import "testing"
+
+func TestMeasureDifficulty(t *testing.T) {
+	maze, err := ParseMaze("ww...w\nww.w.w\nwwAwx.\nww..w.\nww....")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := MeasureDifficulty(maze)
+	if stats.SolutionLength != 6 {
+		t.Errorf("expected length 6 but got %d", stats.SolutionLength)
+	}
+	if stats.DeadEnds != 0 {
+		t.Errorf("expected no dead ends but got %d", stats.DeadEnds)
+	}
+	if stats.Decisions != 1 {
+		t.Errorf("expected 1 decision but got %d", stats.Decisions)
+	}
+	if stats.Score <= 0 || stats.Score >= 1 {
+		t.Errorf("unexpected score: %f", stats.Score)
+	}
+
+	unsolvable, err := ParseMaze("ww..ww\nxw....\nwAwwww")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats = MeasureDifficulty(unsolvable)
+	if stats.SolutionLength != -1 || stats.Score != 1 {
+		t.Errorf("unexpected stats for unsolvable maze: %+v", stats)
+	}
+
+	trivial := testingMaze()
+	trivial.End = trivial.Start
+	stats = MeasureDifficulty(trivial)
+	if stats.SolutionLength != 0 || stats.Decisions != 0 || stats.Score != 0 {
+		t.Errorf("unexpected stats for trivial maze: %+v", stats)
+	}
+}
+
+func TestCurriculumGenerator(t *testing.T) {
+	meanScore := func(difficulty float64) float64 {
+		gen := &CurriculumGenerator{
+			Generators: []Generator{&PrimGenerator{}, &BacktrackerGenerator{}},
+			Difficulty: difficulty,
+		}
+		var total float64
+		for i := 0; i < 10; i++ {
+			m, err := gen.Generate(15, 15)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Rows != 15 || m.Cols != 15 {
+				t.Fatal("invalid dimensions")
+			}
+			total += MeasureDifficulty(m).Score
+		}
+		return total / 10
+	}
+	easy, hard := meanScore(0.1), meanScore(0.9)
+	if easy >= hard {
+		t.Errorf("easy score %f should be less than hard score %f", easy, hard)
+	}
+
+	for _, difficulty := range []float64{-0.5, 1.5} {
+		gen := &CurriculumGenerator{Difficulty: difficulty}
+		if _, err := gen.Generate(11, 11); err == nil {
+			t.Errorf("expected an error for difficulty %f", difficulty)
+		}
+	}
+}
+
+func TestCurriculumGeneratorUpdate(t *testing.T) {
+	gen := &CurriculumGenerator{Difficulty: 0.5, Step: 0.1}
+	gen.Update(0.9)
+	if d := gen.CurrentDifficulty(); d < 0.59 || d > 0.61 {
+		t.Errorf("expected difficulty 0.6 but got %f", d)
+	}
+	gen.Update(0.7)
+	if d := gen.CurrentDifficulty(); d < 0.59 || d > 0.61 {
+		t.Errorf("expected difficulty 0.6 but got %f", d)
+	}
+	for i := 0; i < 10; i++ {
+		gen.Update(0)
+	}
+	if d := gen.CurrentDifficulty(); d != 0 {
+		t.Errorf("expected difficulty 0 but got %f", d)
+	}
+}
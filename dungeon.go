@@ -0,0 +1,230 @@
+package mazenv
+
+import (
+	"errors"
+	"flag"
+	"math"
+	"math/rand"
+)
+
+// DungeonGenerator generates roguelike dungeons made of
+// rectangular rooms joined by corridors.
+type DungeonGenerator struct {
+	// Rooms is the number of rooms to attempt to place.
+	// Fewer rooms may be placed if the grid is crowded.
+	//
+	// If 0, a default of 6 is used.
+	Rooms int
+
+	// MinRoomSize and MaxRoomSize bound the width and
+	// height of each room.
+	//
+	// If 0, defaults of 3 and 7 are used, respectively.
+	MinRoomSize int
+	MaxRoomSize int
+
+	// Winding is the probability that each step of a
+	// corridor goes sideways rather than towards its
+	// destination.
+	// The value may range from 0 (straight corridors) to
+	// 0.9.
+	Winding float64
+
+	// SeparateRooms, if true, places the start and end
+	// in different rooms.
+	// Generation fails if only one room fits in the grid.
+	SeparateRooms bool
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (d *DungeonGenerator) Description() string {
+	return "rooms joined by corridors"
+}
+
+// AddFlags adds the generator's options as flags.
+func (d *DungeonGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.IntVar(&d.Rooms, "rooms", 6, "number of rooms to place")
+	fs.IntVar(&d.MinRoomSize, "min-room", 3, "minimum room width and height")
+	fs.IntVar(&d.MaxRoomSize, "max-room", 7, "maximum room width and height")
+	fs.Float64Var(&d.Winding, "winding", 0, "chance of a corridor going sideways")
+	fs.BoolVar(&d.SeparateRooms, "separate", false,
+		"put the start and end in different rooms")
+}
+
+// Generate generates a random dungeon.
+func (d *DungeonGenerator) Generate(rows, cols int) (*Maze, error) {
+	return d.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (d *DungeonGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	maze, _, err := d.generateRooms(randOrGlobal(rng), rows, cols)
+	return maze, err
+}
+
+// generateRooms is like GenerateRand, but it also returns
+// the rooms that were placed.
+func (d *DungeonGenerator) generateRooms(rng *rand.Rand, rows, cols int) (*Maze,
+	[]dungeonRoom, error) {
+	numRooms, minSize, maxSize := d.adjustedParams()
+	if numRooms < 0 {
+		return nil, nil, errors.New("negative number of rooms")
+	} else if minSize < 0 {
+		return nil, nil, errors.New("negative minimum room size")
+	} else if minSize > maxSize {
+		return nil, nil, errors.New("minimum room size exceeds maximum")
+	}
+
+	maze := &Maze{
+		Rows:  rows,
+		Cols:  cols,
+		Walls: make([]bool, rows*cols),
+	}
+	for i := range maze.Walls {
+		maze.Walls[i] = true
+	}
+
+	rooms := placeRooms(rng, rows, cols, numRooms, minSize, maxSize)
+	if len(rooms) == 0 {
+		return nil, nil, errors.New("no space for rooms")
+	}
+	for _, room := range rooms {
+		for _, p := range room.Positions() {
+			maze.Walls[maze.CellIndex(p)] = false
+		}
+	}
+
+	// Connect each room to the closest connected room.
+	for i := 1; i < len(rooms); i++ {
+		closest := 0
+		for j := 1; j < i; j++ {
+			if rooms[i].Distance(rooms[j]) < rooms[i].Distance(rooms[closest]) {
+				closest = j
+			}
+		}
+		d.carveCorridor(rng, maze, rooms[i].Center(), rooms[closest].Center())
+	}
+
+	if d.SeparateRooms {
+		if len(rooms) < 2 {
+			return nil, nil, errors.New("no space for separate start and end rooms")
+		}
+		perm := rng.Perm(len(rooms))
+		startRoom, endRoom := rooms[perm[0]].Positions(), rooms[perm[1]].Positions()
+		maze.Start = startRoom[rng.Intn(len(startRoom))]
+		maze.End = endRoom[rng.Intn(len(endRoom))]
+	} else if err := placeStartEnd(rng, maze); err != nil {
+		return nil, nil, err
+	}
+
+	return maze, rooms, nil
+}
+
+func (d *DungeonGenerator) adjustedParams() (numRooms, minSize, maxSize int) {
+	numRooms, minSize, maxSize = d.Rooms, d.MinRoomSize, d.MaxRoomSize
+	if numRooms == 0 {
+		numRooms = 6
+	}
+	if minSize == 0 {
+		minSize = 3
+	}
+	if maxSize == 0 {
+		maxSize = 7
+	}
+	return
+}
+
+// carveCorridor opens a path of cells between two points.
+func (d *DungeonGenerator) carveCorridor(rng *rand.Rand, m *Maze, from, to Position) {
+	winding := math.Min(d.Winding, 0.9)
+	cur := from
+	m.Walls[m.CellIndex(cur)] = false
+	for cur != to {
+		rowStep, colStep := sign(to.Row-cur.Row), sign(to.Col-cur.Col)
+		if rowStep != 0 && colStep != 0 {
+			if rng.Intn(2) == 0 {
+				rowStep = 0
+			} else {
+				colStep = 0
+			}
+		}
+		if rng.Float64() < winding {
+			// Step sideways instead.
+			rowStep, colStep = colStep, rowStep
+			if rng.Intn(2) == 0 {
+				rowStep, colStep = -rowStep, -colStep
+			}
+		}
+		next := Position{Row: cur.Row + rowStep, Col: cur.Col + colStep}
+		if m.InBounds(next) {
+			cur = next
+			m.Walls[m.CellIndex(cur)] = false
+		}
+	}
+}
+
+// dungeonRoom is a rectangular room in a dungeon.
+type dungeonRoom struct {
+	Row, Col   int
+	Rows, Cols int
+}
+
+// placeRooms randomly places non-touching rooms.
+func placeRooms(rng *rand.Rand, rows, cols, numRooms, minSize, maxSize int) []dungeonRoom {
+	var res []dungeonRoom
+	for i := 0; i < numRooms*30 && len(res) < numRooms; i++ {
+		room := dungeonRoom{
+			Rows: minSize + rng.Intn(maxSize-minSize+1),
+			Cols: minSize + rng.Intn(maxSize-minSize+1),
+		}
+		if room.Rows > rows || room.Cols > cols {
+			continue
+		}
+		room.Row = rng.Intn(rows - room.Rows + 1)
+		room.Col = rng.Intn(cols - room.Cols + 1)
+		fits := true
+		for _, other := range res {
+			if room.touches(other) {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			res = append(res, room)
+		}
+	}
+	return res
+}
+
+// Positions returns the cells in the room.
+func (d dungeonRoom) Positions() []Position {
+	var res []Position
+	for row := d.Row; row < d.Row+d.Rows; row++ {
+		for col := d.Col; col < d.Col+d.Cols; col++ {
+			res = append(res, Position{Row: row, Col: col})
+		}
+	}
+	return res
+}
+
+// Center returns the cell at the center of the room.
+func (d dungeonRoom) Center() Position {
+	return Position{Row: d.Row + d.Rows/2, Col: d.Col + d.Cols/2}
+}
+
+// Distance computes the Manhattan distance between the
+// centers of two rooms.
+func (d dungeonRoom) Distance(other dungeonRoom) int {
+	c1, c2 := d.Center(), other.Center()
+	return manhattanDistance(c1, c2)
+}
+
+// touches checks if two rooms overlap or are adjacent.
+func (d dungeonRoom) touches(other dungeonRoom) bool {
+	return d.Row <= other.Row+other.Rows && other.Row <= d.Row+d.Rows &&
+		d.Col <= other.Col+other.Cols && other.Col <= d.Col+d.Cols
+}
@@ -0,0 +1,49 @@
+package mazenv
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDungeonGeneratorSeparateRooms(t *testing.T) {
+	gen := &DungeonGenerator{SeparateRooms: true}
+	rng := rand.New(rand.NewSource(1337))
+	for i := 0; i < 10; i++ {
+		m, rooms, err := gen.generateRooms(rng, 21, 21)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roomIndex := func(p Position) int {
+			for i, room := range rooms {
+				if p.Row >= room.Row && p.Row < room.Row+room.Rows &&
+					p.Col >= room.Col && p.Col < room.Col+room.Cols {
+					return i
+				}
+			}
+			return -1
+		}
+		startRoom, endRoom := roomIndex(m.Start), roomIndex(m.End)
+		if startRoom < 0 || endRoom < 0 {
+			t.Errorf("start and end should be in rooms: %#v", m.String())
+		} else if startRoom == endRoom {
+			t.Errorf("start and end are both in room %d: %#v", startRoom, m.String())
+		}
+	}
+
+	// A grid this small only fits one room.
+	if _, err := gen.Generate(5, 5); err == nil {
+		t.Error("expected an error when only one room fits")
+	}
+}
+
+func TestDungeonGeneratorInvalid(t *testing.T) {
+	for _, gen := range []*DungeonGenerator{
+		{Rooms: -1},
+		{MinRoomSize: -1},
+		{MinRoomSize: 5, MaxRoomSize: 4},
+	} {
+		if _, err := gen.Generate(21, 21); err == nil {
+			t.Errorf("expected an error for %+v", gen)
+		}
+	}
+}
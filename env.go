@@ -40,51 +40,33 @@ type Env interface {
 	Position() Position
 }
 
-// rawEnv is a barebones environment for a maze.
-type rawEnv struct {
-	maze     *Maze
+// gridEnv implements the barebones dynamics of NewEnv for
+// any Grid.
+type gridEnv struct {
+	grid     Grid
 	position Position
 }
 
-// NewEnv creates an Env for the maze.
-//
-// Observations are row-major representations of the
-// maze grid.
-// Each cell is represented as a boolean (is current
-// position) followed by a one-hot vector of four
-// components: space, wall, start, end.
-//
-// Rewards are -1 until the maze is solved, at which point
-// the episode ends and the reward is 0.
-// This way, shorter solutions are preferred.
-func NewEnv(maze *Maze) Env {
-	return &rawEnv{maze: maze}
-}
-
-// Maze returns the maze.
-func (r *rawEnv) Maze() *Maze {
-	return r.maze
-}
-
 // Position returns the current position.
-func (r *rawEnv) Position() Position {
-	return r.position
+func (g *gridEnv) Position() Position {
+	return g.position
 }
 
 // Reset resets the player's position to the start.
-func (r *rawEnv) Reset() (obs []float64, err error) {
-	r.position = r.maze.Start
-	return r.observation(), nil
+func (g *gridEnv) Reset() (obs []float64, err error) {
+	g.position, _ = g.grid.Endpoints()
+	return g.observation(), nil
 }
 
 // Step takes a step in the environment.
-func (r *rawEnv) Step(action []float64) (obs []float64, reward float64,
+func (g *gridEnv) Step(action []float64) (obs []float64, reward float64,
 	done bool, err error) {
-	if r.position == r.maze.End {
+	_, end := g.grid.Endpoints()
+	if g.position == end {
 		err = errors.New("step: maze is already solved")
 		return
 	}
-	newPos := r.position
+	newPos := g.position
 	var actionIdx int
 	for i, x := range action {
 		if x != 0 {
@@ -101,19 +83,46 @@ func (r *rawEnv) Step(action []float64) (obs []float64, reward float64,
 	case ActionLeft:
 		newPos.Col--
 	}
-	if !r.maze.Wall(newPos) {
-		r.position = newPos
+	if !g.grid.Wall(newPos) {
+		g.position = newPos
 	}
-	if r.position == r.maze.End {
+	if g.position == end {
 		reward = 0
 		done = true
 	} else {
 		reward = -1
 	}
-	obs = r.observation()
+	obs = g.observation()
 	return
 }
 
-func (r *rawEnv) observation() []float64 {
-	return oneHotGrid(r.maze, r.position, 0, 0, r.maze.Rows, r.maze.Cols)
+func (g *gridEnv) observation() []float64 {
+	rows, cols := g.grid.Size()
+	return oneHotGrid(g.grid, g.position, 0, 0, rows, cols)
+}
+
+// rawEnv is a barebones environment for a maze.
+type rawEnv struct {
+	gridEnv
+	maze *Maze
+}
+
+// NewEnv creates an Env for the maze.
+//
+// Observations are row-major representations of the
+// maze grid.
+// Each cell is represented as a boolean (is current
+// position) followed by a one-hot vector of four
+// components: space, wall, start, end.
+//
+// Rewards are -1 until the maze is solved, at which point
+// the episode ends and the reward is 0.
+// This way, shorter solutions are preferred.
+func NewEnv(maze *Maze) Env {
+	return &rawEnv{gridEnv: gridEnv{grid: maze}, maze: maze}
+}
+
+// Maze returns the maze.
+func (r *rawEnv) Maze() *Maze {
+	return r.maze
 }
@@ -3,6 +3,7 @@ package mazenv
 import (
 	"errors"
 	"flag"
+	"math"
 	"math/rand"
 
 	"github.com/unixpickle/essentials"
@@ -13,6 +14,37 @@ type Generator interface {
 	Generate(rows, cols int) (*Maze, error)
 }
 
+// A RandGenerator is a Generator which can draw its random
+// numbers from a specific source.
+//
+// Given sources with the same seed, GenerateRand should
+// produce the same maze every time.
+type RandGenerator interface {
+	Generator
+	GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error)
+}
+
+// GenerateSeed generates a maze deterministically from a
+// seed, independently of any other random sources.
+//
+// The generator must implement RandGenerator.
+func GenerateSeed(g Generator, seed int64, rows, cols int) (*Maze, error) {
+	randGen, ok := g.(RandGenerator)
+	if !ok {
+		return nil, errors.New("generate from seed: generator does not support seeding")
+	}
+	return randGen.GenerateRand(rand.New(rand.NewSource(seed)), rows, cols)
+}
+
+// generateRand generates a maze using rng if the generator
+// supports it, or the generator's own source otherwise.
+func generateRand(g Generator, rng *rand.Rand, rows, cols int) (*Maze, error) {
+	if randGen, ok := g.(RandGenerator); ok {
+		return randGen.GenerateRand(rng, rows, cols)
+	}
+	return g.Generate(rows, cols)
+}
+
 // PrimGenerator is a Generator that uses a randomized
 // variant of Prim's algorithm.
 type PrimGenerator struct{}
@@ -31,12 +63,21 @@ func (p *PrimGenerator) AddFlags(f *flag.FlagSet) {
 
 // Generate generates a random maze.
 func (p *PrimGenerator) Generate(rows, cols int) (*Maze, error) {
+	return p.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (p *PrimGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
 	maze := &Maze{
 		Rows: rows,
 		Cols: cols,
 		Start: Position{
-			Row: rand.Intn(rows),
-			Col: rand.Intn(cols),
+			Row: rng.Intn(rows),
+			Col: rng.Intn(cols),
 		},
 		Walls: make([]bool, rows*cols),
 	}
@@ -52,7 +93,7 @@ func (p *PrimGenerator) Generate(rows, cols int) (*Maze, error) {
 		visited[p] = true
 	}
 	for len(edges) > 0 {
-		idx := rand.Intn(len(edges))
+		idx := rng.Intn(len(edges))
 		pos := edges[idx]
 		essentials.UnorderedDelete(&edges, idx)
 		if len(neighboringSpaces(maze, pos)) > 1 {
@@ -67,7 +108,7 @@ func (p *PrimGenerator) Generate(rows, cols int) (*Maze, error) {
 		}
 	}
 
-	ends := shuffledSpaces(maze, maze.Start)
+	ends := shuffledSpaces(rng, maze, maze.Start)
 	if len(ends) == 0 {
 		return nil, errors.New("no options for end")
 	}
@@ -111,6 +152,15 @@ func (i *IslandGenerator) AddFlags(fs *flag.FlagSet) {
 //
 // Both dimensions must be odd.
 func (i *IslandGenerator) Generate(rows, cols int) (*Maze, error) {
+	return i.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (i *IslandGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
 	if rows%2 == 0 || cols%2 == 0 {
 		return nil, errors.New("maze dimensions must be odd")
 	}
@@ -127,8 +177,8 @@ func (i *IslandGenerator) Generate(rows, cols int) (*Maze, error) {
 		// Select an island start, which can possibly be on
 		// the border around the grid.
 		curPos := Position{
-			Row: rand.Intn(rows/2+2)*2 - 1,
-			Col: rand.Intn(cols/2+2)*2 - 1,
+			Row: rng.Intn(rows/2+2)*2 - 1,
+			Col: rng.Intn(cols/2+2)*2 - 1,
 		}
 
 		if maze.InBounds(curPos) {
@@ -140,7 +190,7 @@ func (i *IslandGenerator) Generate(rows, cols int) (*Maze, error) {
 			if len(destinations) == 0 {
 				break
 			}
-			destination := destinations[rand.Intn(len(destinations))]
+			destination := destinations[rng.Intn(len(destinations))]
 			midpoint := Position{
 				Row: curPos.Row + (destination.Row-curPos.Row)/2,
 				Col: curPos.Col + (destination.Col-curPos.Col)/2,
@@ -152,7 +202,7 @@ func (i *IslandGenerator) Generate(rows, cols int) (*Maze, error) {
 		}
 	}
 
-	spaces := shuffledSpaces(maze)
+	spaces := shuffledSpaces(rng, maze)
 	if len(spaces) < 2 {
 		return nil, errors.New("not enough spaces")
 	}
@@ -177,6 +227,769 @@ func (i *IslandGenerator) adjustedParams(rows, cols int) (numIslands, islandSize
 	return
 }
 
+// BacktrackerGenerator is a Generator that uses a
+// randomized depth-first search.
+//
+// Mazes from this generator tend to have long, winding
+// corridors with few branches.
+type BacktrackerGenerator struct {
+	// Straightness is the probability of continuing a
+	// corridor in the same direction when possible.
+	// The value may range from 0 to 1.
+	Straightness float64
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (b *BacktrackerGenerator) Description() string {
+	return "randomized depth-first search (long corridors)"
+}
+
+// AddFlags adds the generator's options as flags.
+func (b *BacktrackerGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&b.Straightness, "straightness", 0,
+		"chance of continuing a corridor in the same direction")
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (b *BacktrackerGenerator) Generate(rows, cols int) (*Maze, error) {
+	return b.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (b *BacktrackerGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	maze, err := latticeMaze(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := latticeCells(maze)
+	first := cells[rng.Intn(len(cells))]
+	maze.Walls[maze.CellIndex(first)] = false
+
+	stack := []Position{first}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		var options []Position
+		for _, p := range latticeNeighbors(maze, cur) {
+			if maze.Wall(p) {
+				options = append(options, p)
+			}
+		}
+		if len(options) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		next := options[rng.Intn(len(options))]
+		if len(stack) > 1 && rng.Float64() < b.Straightness {
+			prev := stack[len(stack)-2]
+			straight := Position{
+				Row: 2*cur.Row - prev.Row,
+				Col: 2*cur.Col - prev.Col,
+			}
+			for _, p := range options {
+				if p == straight {
+					next = straight
+				}
+			}
+		}
+		carvePassage(maze, cur, next)
+		stack = append(stack, next)
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// WilsonGenerator is a Generator that uses Wilson's
+// algorithm to sample uniformly from all perfect mazes.
+type WilsonGenerator struct{}
+
+// Description returns a short description of what the
+// algorithm does.
+func (w *WilsonGenerator) Description() string {
+	return "Wilson's algorithm (uniform spanning tree)"
+}
+
+// AddFlags adds the generator's options as flags.
+//
+// Currently, this is a no-op.
+func (w *WilsonGenerator) AddFlags(fs *flag.FlagSet) {
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (w *WilsonGenerator) Generate(rows, cols int) (*Maze, error) {
+	return w.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (w *WilsonGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	maze, err := latticeMaze(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := latticeCells(maze)
+	root := cells[rng.Intn(len(cells))]
+	maze.Walls[maze.CellIndex(root)] = false
+
+	for _, i := range rng.Perm(len(cells)) {
+		if !maze.Wall(cells[i]) {
+			continue
+		}
+
+		// Perform a random walk until we hit the maze,
+		// only remembering the last exit from each cell.
+		// This erases loops from the walk.
+		exits := map[Position]Position{}
+		cur := cells[i]
+		for maze.Wall(cur) {
+			options := latticeNeighbors(maze, cur)
+			next := options[rng.Intn(len(options))]
+			exits[cur] = next
+			cur = next
+		}
+
+		for end, cur := cur, cells[i]; cur != end; cur = exits[cur] {
+			carvePassage(maze, cur, exits[cur])
+		}
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// AldousBroderGenerator is a Generator that uses the
+// Aldous-Broder algorithm to sample uniformly from all
+// perfect mazes.
+//
+// This is typically slower than WilsonGenerator, but it
+// produces mazes from the same distribution.
+type AldousBroderGenerator struct{}
+
+// Description returns a short description of what the
+// algorithm does.
+func (a *AldousBroderGenerator) Description() string {
+	return "Aldous-Broder algorithm (uniform spanning tree)"
+}
+
+// AddFlags adds the generator's options as flags.
+//
+// Currently, this is a no-op.
+func (a *AldousBroderGenerator) AddFlags(fs *flag.FlagSet) {
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (a *AldousBroderGenerator) Generate(rows, cols int) (*Maze, error) {
+	return a.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (a *AldousBroderGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	maze, err := latticeMaze(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := latticeCells(maze)
+	cur := cells[rng.Intn(len(cells))]
+	maze.Walls[maze.CellIndex(cur)] = false
+	remaining := len(cells) - 1
+
+	for remaining > 0 {
+		options := latticeNeighbors(maze, cur)
+		next := options[rng.Intn(len(options))]
+		if maze.Wall(next) {
+			carvePassage(maze, cur, next)
+			remaining--
+		}
+		cur = next
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// KruskalGenerator is a Generator that uses a randomized
+// variant of Kruskal's algorithm.
+//
+// Mazes from this generator tend to have many short dead
+// ends.
+type KruskalGenerator struct{}
+
+// Description returns a short description of what the
+// algorithm does.
+func (k *KruskalGenerator) Description() string {
+	return "randomized variant of Kruskal's algorithm"
+}
+
+// AddFlags adds the generator's options as flags.
+//
+// Currently, this is a no-op.
+func (k *KruskalGenerator) AddFlags(fs *flag.FlagSet) {
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (k *KruskalGenerator) Generate(rows, cols int) (*Maze, error) {
+	return k.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (k *KruskalGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	maze, err := latticeMaze(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	type edge struct {
+		From Position
+		To   Position
+	}
+	var edges []edge
+	for _, cell := range latticeCells(maze) {
+		maze.Walls[maze.CellIndex(cell)] = false
+		for _, next := range []Position{{cell.Row + 2, cell.Col}, {cell.Row, cell.Col + 2}} {
+			if maze.InBounds(next) {
+				edges = append(edges, edge{From: cell, To: next})
+			}
+		}
+	}
+
+	sets := newDisjointSet(len(maze.Walls))
+	for _, i := range rng.Perm(len(edges)) {
+		e := edges[i]
+		if sets.Union(maze.CellIndex(e.From), maze.CellIndex(e.To)) {
+			carvePassage(maze, e.From, e.To)
+		}
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// EllerGenerator is a Generator that uses Eller's
+// algorithm, which builds a maze one row at a time.
+//
+// Since only one row of state is needed at a time, the
+// GenerateRows method can stream very wide mazes using
+// memory proportional to the number of columns.
+type EllerGenerator struct {
+	// JoinProbability is the probability of joining two
+	// adjacent cells in a row.
+	// The value may range from 0 to 1.
+	//
+	// If 0, a default of 0.5 is used.
+	JoinProbability float64
+
+	// ExtendProbability is the probability of extending a
+	// cell down to the next row, beyond the one extension
+	// that every set requires.
+	// The value may range from 0 to 1.
+	//
+	// If 0, a default of 0.5 is used.
+	ExtendProbability float64
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (e *EllerGenerator) Description() string {
+	return "Eller's algorithm (builds one row at a time)"
+}
+
+// AddFlags adds the generator's options as flags.
+func (e *EllerGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&e.JoinProbability, "join", 0.5,
+		"chance of joining horizontally adjacent cells")
+	fs.Float64Var(&e.ExtendProbability, "extend", 0.5,
+		"chance of extending a cell down to the next row")
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (e *EllerGenerator) Generate(rows, cols int) (*Maze, error) {
+	return e.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (e *EllerGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	maze := &Maze{Rows: rows, Cols: cols}
+	err := e.GenerateRowsRand(rng, rows, cols, func(row []bool) error {
+		maze.Walls = append(maze.Walls, row...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// GenerateRows generates a random maze and passes each
+// row of walls to f, from top to bottom.
+//
+// The slice passed to f is reused between calls.
+// If f returns an error, generation stops and the error
+// is returned.
+//
+// Both dimensions must be odd.
+func (e *EllerGenerator) GenerateRows(rows, cols int, f func(row []bool) error) error {
+	return e.GenerateRowsRand(nil, rows, cols, f)
+}
+
+// GenerateRowsRand is like GenerateRows, but it draws
+// random numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (e *EllerGenerator) GenerateRowsRand(rng *rand.Rand, rows, cols int,
+	f func(row []bool) error) error {
+	rng = randOrGlobal(rng)
+	if rows%2 == 0 || cols%2 == 0 {
+		return errors.New("maze dimensions must be odd")
+	}
+	joinProb, extendProb := e.JoinProbability, e.ExtendProbability
+	if joinProb == 0 {
+		joinProb = 0.5
+	}
+	if extendProb == 0 {
+		extendProb = 0.5
+	}
+
+	numCells := (cols + 1) / 2
+	sets := make([]int, numCells)
+	var nextSet int
+	row := make([]bool, cols)
+	for cellRow := 0; cellRow < (rows+1)/2; cellRow++ {
+		lastRow := cellRow == (rows-1)/2
+		for i, set := range sets {
+			if set == 0 {
+				nextSet++
+				sets[i] = nextSet
+			}
+		}
+
+		for i := range row {
+			row[i] = i%2 == 1
+		}
+		for i := 0; i+1 < numCells; i++ {
+			if sets[i] == sets[i+1] || !(lastRow || rng.Float64() < joinProb) {
+				continue
+			}
+			row[2*i+1] = false
+			oldSet := sets[i+1]
+			for j, set := range sets {
+				if set == oldSet {
+					sets[j] = sets[i]
+				}
+			}
+		}
+		if err := f(row); err != nil {
+			return err
+		}
+		if lastRow {
+			break
+		}
+
+		var order []int
+		members := map[int][]int{}
+		for i, set := range sets {
+			if _, ok := members[set]; !ok {
+				order = append(order, set)
+			}
+			members[set] = append(members[set], i)
+		}
+		for i := range row {
+			row[i] = true
+		}
+		for _, set := range order {
+			cells := members[set]
+			for j, k := range rng.Perm(len(cells)) {
+				if j == 0 || rng.Float64() < extendProb {
+					row[2*cells[k]] = false
+				} else {
+					sets[cells[k]] = 0
+				}
+			}
+		}
+		if err := f(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SidewinderGenerator is a Generator that uses the
+// Sidewinder algorithm.
+//
+// Mazes from this generator always have an open corridor
+// along the top row, and paths tend to run vertically.
+type SidewinderGenerator struct {
+	// RunProbability is the probability of continuing a
+	// horizontal run rather than carving upwards.
+	// The value may range from 0 to 1.
+	//
+	// If 0, a default of 0.5 is used.
+	RunProbability float64
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (s *SidewinderGenerator) Description() string {
+	return "Sidewinder algorithm (open top corridor)"
+}
+
+// AddFlags adds the generator's options as flags.
+func (s *SidewinderGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&s.RunProbability, "run", 0.5,
+		"chance of continuing a horizontal run")
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (s *SidewinderGenerator) Generate(rows, cols int) (*Maze, error) {
+	return s.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (s *SidewinderGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	maze, err := latticeMaze(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	runProb := s.RunProbability
+	if runProb == 0 {
+		runProb = 0.5
+	}
+
+	for row := 0; row < rows; row += 2 {
+		var run []Position
+		for col := 0; col < cols; col += 2 {
+			cell := Position{Row: row, Col: col}
+			maze.Walls[maze.CellIndex(cell)] = false
+			run = append(run, cell)
+			east := Position{Row: row, Col: col + 2}
+			if !maze.InBounds(east) || (row > 0 && rng.Float64() >= runProb) {
+				if row > 0 {
+					up := run[rng.Intn(len(run))]
+					carvePassage(maze, up, Position{Row: up.Row - 2, Col: up.Col})
+				}
+				run = nil
+			} else {
+				carvePassage(maze, cell, east)
+			}
+		}
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// BinaryTreeGenerator is a Generator that uses the Binary
+// Tree algorithm.
+//
+// Each cell is connected to a neighbor in one of two
+// directions, so paths are strongly biased towards one
+// diagonal.
+type BinaryTreeGenerator struct {
+	// Direction is the diagonal which paths lead to.
+	// It may be "ne", "nw", "se", or "sw".
+	//
+	// If empty, a default of "ne" is used.
+	Direction string
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (b *BinaryTreeGenerator) Description() string {
+	return "Binary Tree algorithm (diagonal bias)"
+}
+
+// AddFlags adds the generator's options as flags.
+func (b *BinaryTreeGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&b.Direction, "direction", "ne",
+		"diagonal direction of the bias (ne, nw, se, or sw)")
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (b *BinaryTreeGenerator) Generate(rows, cols int) (*Maze, error) {
+	return b.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (b *BinaryTreeGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	var rowStep, colStep int
+	switch b.Direction {
+	case "", "ne":
+		rowStep, colStep = -2, 2
+	case "nw":
+		rowStep, colStep = -2, -2
+	case "se":
+		rowStep, colStep = 2, 2
+	case "sw":
+		rowStep, colStep = 2, -2
+	default:
+		return nil, errors.New("unknown direction: " + b.Direction)
+	}
+
+	maze, err := latticeMaze(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cell := range latticeCells(maze) {
+		maze.Walls[maze.CellIndex(cell)] = false
+		var options []Position
+		for _, p := range []Position{{cell.Row + rowStep, cell.Col}, {cell.Row, cell.Col + colStep}} {
+			if maze.InBounds(p) {
+				options = append(options, p)
+			}
+		}
+		if len(options) > 0 {
+			carvePassage(maze, cell, options[rng.Intn(len(options))])
+		}
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// DivisionGenerator is a Generator that uses recursive
+// division.
+//
+// Unlike most generators, it starts with an open room and
+// adds walls to it, splitting it into smaller and smaller
+// chambers.
+// Every wall has a single gap, so the result is connected.
+type DivisionGenerator struct {
+	// MinChamber is the chamber size, in lattice cells,
+	// below which chambers are no longer divided.
+	// Chambers whose width and height are both at most
+	// MinChamber are left as open rooms.
+	//
+	// If 0, a default of 1 is used, resulting in a
+	// perfect maze.
+	MinChamber int
+
+	// HorizontalBias is the probability of dividing a
+	// chamber with a horizontal wall when both directions
+	// are possible.
+	// Values outside of the range 0 to 1 are clamped, so a
+	// negative value only divides vertically.
+	//
+	// If 0, a default of 0.5 is used.
+	HorizontalBias float64
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (d *DivisionGenerator) Description() string {
+	return "recursive division (adds walls to an open room)"
+}
+
+// AddFlags adds the generator's options as flags.
+func (d *DivisionGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.IntVar(&d.MinChamber, "min-chamber", 1,
+		"size of chambers which are left as open rooms")
+	fs.Float64Var(&d.HorizontalBias, "horizontal", 0.5,
+		"chance of dividing a chamber horizontally (negative for never)")
+}
+
+// Generate generates a random maze.
+//
+// Both dimensions must be odd.
+func (d *DivisionGenerator) Generate(rows, cols int) (*Maze, error) {
+	return d.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (d *DivisionGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	if rows%2 == 0 || cols%2 == 0 {
+		return nil, errors.New("maze dimensions must be odd")
+	}
+	minChamber := essentials.MaxInt(d.MinChamber, 1)
+	horizontalBias := d.HorizontalBias
+	if horizontalBias == 0 {
+		horizontalBias = 0.5
+	}
+	horizontalBias = math.Max(0, math.Min(1, horizontalBias))
+
+	maze := &Maze{
+		Rows:  rows,
+		Cols:  cols,
+		Walls: make([]bool, rows*cols),
+	}
+
+	// Chambers are measured in lattice cells, with
+	// inclusive minimums and exclusive maximums.
+	type chamber struct {
+		MinRow, MaxRow int
+		MinCol, MaxCol int
+	}
+	chambers := []chamber{{MaxRow: (rows + 1) / 2, MaxCol: (cols + 1) / 2}}
+	for len(chambers) > 0 {
+		c := chambers[len(chambers)-1]
+		chambers = chambers[:len(chambers)-1]
+		height, width := c.MaxRow-c.MinRow, c.MaxCol-c.MinCol
+		if height <= minChamber && width <= minChamber {
+			continue
+		}
+		if height > 1 && (width == 1 || rng.Float64() < horizontalBias) {
+			split := c.MinRow + 1 + rng.Intn(height-1)
+			gap := c.MinCol + rng.Intn(width)
+			for col := 2 * c.MinCol; col < 2*c.MaxCol-1; col++ {
+				if col != 2*gap {
+					maze.Walls[maze.CellIndex(Position{Row: 2*split - 1, Col: col})] = true
+				}
+			}
+			top, bottom := c, c
+			top.MaxRow, bottom.MinRow = split, split
+			chambers = append(chambers, top, bottom)
+		} else {
+			split := c.MinCol + 1 + rng.Intn(width-1)
+			gap := c.MinRow + rng.Intn(height)
+			for row := 2 * c.MinRow; row < 2*c.MaxRow-1; row++ {
+				if row != 2*gap {
+					maze.Walls[maze.CellIndex(Position{Row: row, Col: 2*split - 1})] = true
+				}
+			}
+			left, right := c, c
+			left.MaxCol, right.MinCol = split, split
+			chambers = append(chambers, left, right)
+		}
+	}
+
+	if err := placeStartEnd(rng, maze); err != nil {
+		return nil, err
+	}
+	return maze, nil
+}
+
+// latticeMaze creates a maze filled with walls for
+// generators that connect a lattice of cells.
+//
+// The cells of the lattice are the positions where both
+// the row and column are even.
+// Passages between cells are the positions in between.
+func latticeMaze(rows, cols int) (*Maze, error) {
+	if rows%2 == 0 || cols%2 == 0 {
+		return nil, errors.New("maze dimensions must be odd")
+	}
+	maze := &Maze{
+		Rows:  rows,
+		Cols:  cols,
+		Walls: make([]bool, rows*cols),
+	}
+	for i := range maze.Walls {
+		maze.Walls[i] = true
+	}
+	return maze, nil
+}
+
+// latticeCells returns the cells of a lattice maze.
+func latticeCells(m *Maze) []Position {
+	var res []Position
+	for row := 0; row < m.Rows; row += 2 {
+		for col := 0; col < m.Cols; col += 2 {
+			res = append(res, Position{Row: row, Col: col})
+		}
+	}
+	return res
+}
+
+// latticeNeighbors returns the lattice cells which are
+// adjacent to a lattice cell.
+func latticeNeighbors(m *Maze, p Position) []Position {
+	var res []Position
+	for _, delta := range []int{-2, 2} {
+		for _, n := range []Position{{p.Row + delta, p.Col}, {p.Row, p.Col + delta}} {
+			if m.InBounds(n) {
+				res = append(res, n)
+			}
+		}
+	}
+	return res
+}
+
+// carvePassage opens two adjacent lattice cells and the
+// wall between them.
+func carvePassage(m *Maze, from, to Position) {
+	midpoint := Position{
+		Row: (from.Row + to.Row) / 2,
+		Col: (from.Col + to.Col) / 2,
+	}
+	for _, p := range []Position{from, midpoint, to} {
+		m.Walls[m.CellIndex(p)] = false
+	}
+}
+
+// placeStartEnd picks distinct, random spaces for the
+// start and end of a maze.
+func placeStartEnd(rng *rand.Rand, m *Maze) error {
+	spaces := shuffledSpaces(rng, m)
+	if len(spaces) < 2 {
+		return errors.New("not enough spaces")
+	}
+	m.Start, m.End = spaces[0], spaces[1]
+	return nil
+}
+
 func spacesTwoCellsAway(m *Maze, pos Position) []Position {
 	var res []Position
 	for _, delta := range []int{-2, 2} {
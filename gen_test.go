@@ -1,13 +1,12 @@
 package mazenv
 
-import "testing"
+import (
+	"math/rand"
+	"testing"
+)
 
 func TestGenerators(t *testing.T) {
-	gens := map[string]Generator{
-		"PrimGenerator":   &PrimGenerator{},
-		"IslandGenerator": &IslandGenerator{},
-	}
-	for name, gen := range gens {
+	for name, gen := range testGenerators() {
 		t.Run(name, func(t *testing.T) {
 			var seen []*Maze
 			for i := 0; i < 5; i++ {
@@ -42,3 +41,182 @@ func TestGenerators(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSeed(t *testing.T) {
+	for name, gen := range testGenerators() {
+		t.Run(name, func(t *testing.T) {
+			results := make(chan string, 4)
+			for i := 0; i < 4; i++ {
+				go func(seed int64) {
+					m, err := GenerateSeed(gen, seed, 21, 15)
+					if err != nil {
+						t.Error(err)
+						results <- ""
+						return
+					}
+					results <- m.String()
+				}(int64(i % 2))
+			}
+			seen := map[string]int{}
+			for i := 0; i < 4; i++ {
+				seen[<-results]++
+			}
+			if len(seen) != 2 {
+				t.Fatalf("expected 2 distinct mazes but got %d", len(seen))
+			}
+			for _, count := range seen {
+				if count != 2 {
+					t.Error("same seed produced different mazes")
+				}
+			}
+		})
+	}
+}
+
+func TestPerfectGenerators(t *testing.T) {
+	gens := map[string]Generator{
+		"PrimGenerator":         &PrimGenerator{},
+		"BacktrackerGenerator":  &BacktrackerGenerator{},
+		"WilsonGenerator":       &WilsonGenerator{},
+		"AldousBroderGenerator": &AldousBroderGenerator{},
+		"KruskalGenerator":      &KruskalGenerator{},
+		"EllerGenerator":        &EllerGenerator{JoinProbability: 0.3},
+		"SidewinderGenerator":   &SidewinderGenerator{RunProbability: 0.8},
+		"BinaryTreeGenerator":   &BinaryTreeGenerator{},
+		"DivisionGenerator":     &DivisionGenerator{HorizontalBias: 0.7},
+	}
+	for name, gen := range gens {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				m, err := gen.Generate(21, 15)
+				if err != nil {
+					t.Fatal(err)
+				}
+				testPerfectMaze(t, m)
+			}
+		})
+	}
+}
+
+func TestEllerGenerateRows(t *testing.T) {
+	var numRows int
+	err := (&EllerGenerator{}).GenerateRows(9, 301, func(row []bool) error {
+		if len(row) != 301 {
+			t.Fatalf("row %d has length %d", numRows, len(row))
+		}
+		numRows++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numRows != 9 {
+		t.Errorf("expected 9 rows but got %d", numRows)
+	}
+}
+
+func TestDivisionGeneratorChambers(t *testing.T) {
+	// A 3x3 chamber of lattice cells spans 5x5 cells.
+	gen := &DivisionGenerator{MinChamber: 3}
+	rng := rand.New(rand.NewSource(1337))
+	var foundChamber bool
+	for i := 0; i < 10; i++ {
+		m, err := gen.GenerateRand(rng, 21, 21)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hasOpenSquare(m, 6) {
+			t.Fatalf("chamber is too large: %#v", m.String())
+		}
+		foundChamber = foundChamber || hasOpenSquare(m, 5)
+	}
+	if !foundChamber {
+		t.Error("no open chambers")
+	}
+
+	// Chambers that are 3 lattice cells tall are never
+	// divided horizontally.
+	gen = &DivisionGenerator{MinChamber: 3, HorizontalBias: -1}
+	m, err := gen.GenerateRand(rng, 5, 21)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range m.Positions() {
+		if m.Wall(p) && p.Col%2 == 0 {
+			t.Fatalf("unexpected horizontal wall: %#v", m.String())
+		}
+	}
+}
+
+// hasOpenSquare checks if a maze has a size x size square
+// without any walls.
+func hasOpenSquare(m *Maze, size int) bool {
+	for row := 0; row+size <= m.Rows; row++ {
+		for col := 0; col+size <= m.Cols; col++ {
+			open := true
+			for i := 0; i < size*size && open; i++ {
+				open = !m.Wall(Position{Row: row + i/size, Col: col + i%size})
+			}
+			if open {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func testGenerators() map[string]Generator {
+	return map[string]Generator{
+		"PrimGenerator":         &PrimGenerator{},
+		"IslandGenerator":       &IslandGenerator{},
+		"BacktrackerGenerator":  &BacktrackerGenerator{Straightness: 0.5},
+		"WilsonGenerator":       &WilsonGenerator{},
+		"AldousBroderGenerator": &AldousBroderGenerator{},
+		"KruskalGenerator":      &KruskalGenerator{},
+		"EllerGenerator":        &EllerGenerator{},
+		"SidewinderGenerator":   &SidewinderGenerator{},
+		"BinaryTreeGenerator":   &BinaryTreeGenerator{Direction: "sw"},
+		"DivisionGenerator":     &DivisionGenerator{MinChamber: 3},
+		"CaveGenerator":         &CaveGenerator{},
+		"CaveGenerator/Connect": &CaveGenerator{ConnectAll: true},
+		"DungeonGenerator":      &DungeonGenerator{Winding: 0.3, SeparateRooms: true},
+	}
+}
+
+// testPerfectMaze checks that the spaces in a maze form a
+// tree, i.e. that they are connected and have no loops.
+func testPerfectMaze(t *testing.T, m *Maze) {
+	var numSpaces, numEdges int
+	var first Position
+	for _, p := range m.Positions() {
+		if m.Wall(p) {
+			continue
+		}
+		if numSpaces == 0 {
+			first = p
+		}
+		numSpaces++
+		numEdges += len(neighboringSpaces(m, p))
+	}
+	numEdges /= 2
+
+	visited := map[Position]bool{first: true}
+	queue := []Position{first}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, n := range neighboringSpaces(m, p) {
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	if len(visited) != numSpaces {
+		t.Errorf("maze is not connected: %#v", m.String())
+	}
+	if numEdges != numSpaces-1 {
+		t.Errorf("maze has loops: %#v", m.String())
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/unixpickle/essentials"
@@ -18,6 +23,19 @@ type CommonFlags struct {
 	Seed   int
 	Num    int
 	Border bool
+	Braid  float64
+	Mask   string
+
+	Symmetry string
+
+	Format string
+	Style  string
+	Dedupe bool
+
+	MinLength  int
+	MaxLength  int
+	Farthest   bool
+	Percentile float64
 }
 
 func (c *CommonFlags) AddFlags(f *flag.FlagSet) {
@@ -26,17 +44,132 @@ func (c *CommonFlags) AddFlags(f *flag.FlagSet) {
 	f.IntVar(&c.Seed, "seed", -1, "random number generator seed (-1 for random)")
 	f.IntVar(&c.Num, "num", 1, "number of mazes to generate")
 	f.BoolVar(&c.Border, "border", false, "add a border of walls around the maze")
+	f.Float64Var(&c.Braid, "braid", 0, "fraction of dead ends to remove by adding loops")
+	f.StringVar(&c.Mask, "mask", "",
+		"PNG/JPEG or text file marking the usable cells (text masks set the dimensions)")
+	f.StringVar(&c.Symmetry, "symmetry", "",
+		"make the maze symmetric (horizontal, vertical, or rotational)")
+	f.StringVar(&c.Format, "format", "text",
+		"output format (text, json, jsonl with metadata, or binary)")
+	f.StringVar(&c.Style, "style", "plain",
+		"text style (plain, block for Unicode blocks, or ansi for colored blocks)")
+	f.BoolVar(&c.Dedupe, "dedupe", false,
+		"skip mazes which are rotations or reflections of earlier ones")
+	f.IntVar(&c.MinLength, "min-length", 0, "minimum solution length (in steps)")
+	f.IntVar(&c.MaxLength, "max-length", 0, "maximum solution length (0 for no limit)")
+	f.BoolVar(&c.Farthest, "farthest", false, "place the end as far from the start as possible")
+	f.Float64Var(&c.Percentile, "percentile", 0,
+		"place the end at this percentile (0 to 1) of distances from the start")
 }
 
+// Wrap applies generator-independent options to a
+// generator.
+//
+// If a text mask is used, Rows and Cols are updated to
+// match it.
+//
+// Symmetry cannot be combined with a mask, since opening
+// walls in mirrored pairs may carve into masked-out cells,
+// or with length constraints, since moving the start and
+// end would break their symmetric placement.
+func (c *CommonFlags) Wrap(g mazenv.RandGenerator) (mazenv.RandGenerator, error) {
+	lengthConstrained := c.MinLength > 0 || c.MaxLength > 0 || c.Farthest || c.Percentile != 0
+	if c.Symmetry != "" {
+		if c.Mask != "" {
+			return nil, errors.New("-symmetry cannot be combined with -mask")
+		} else if lengthConstrained {
+			return nil, errors.New("-symmetry cannot be combined with " +
+				"-min-length, -max-length, -farthest, or -percentile")
+		}
+	}
+	// Braiding happens before masking, since it may open
+	// walls anywhere in the grid.
+	if c.Braid > 0 {
+		g = &braidGenerator{Generator: g, Fraction: c.Braid}
+	}
+	if c.Mask != "" {
+		mask, err := c.readMask()
+		if err != nil {
+			return nil, err
+		}
+		c.Rows, c.Cols = mask.Rows, mask.Cols
+		g = &mazenv.MaskedGenerator{Generator: g, Mask: mask}
+	}
+	if c.Symmetry != "" {
+		g = &mazenv.SymmetricGenerator{Generator: g, Symmetry: c.Symmetry}
+	}
+	if lengthConstrained {
+		g = &mazenv.LengthGenerator{
+			Generator:  g,
+			MinLength:  c.MinLength,
+			MaxLength:  c.MaxLength,
+			Farthest:   c.Farthest,
+			Percentile: c.Percentile,
+		}
+	}
+	return g, nil
+}
+
+func (c *CommonFlags) readMask() (*mazenv.Mask, error) {
+	switch strings.ToLower(filepath.Ext(c.Mask)) {
+	case ".png", ".jpg", ".jpeg":
+		f, err := os.Open(c.Mask)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return mazenv.ReadMaskImage(f, c.Rows, c.Cols)
+	default:
+		data, err := ioutil.ReadFile(c.Mask)
+		if err != nil {
+			return nil, err
+		}
+		return mazenv.ParseMask(string(data))
+	}
+}
+
+// maxDuplicates is the number of duplicates in a row
+// after which -dedupe gives up.
+const maxDuplicates = 1000
+
 type Generator interface {
-	mazenv.Generator
+	mazenv.RandGenerator
 	Description() string
 	AddFlags(f *flag.FlagSet)
 }
 
+// braidGenerator braids the mazes from a generator.
+type braidGenerator struct {
+	Generator mazenv.RandGenerator
+	Fraction  float64
+}
+
+func (b *braidGenerator) Generate(rows, cols int) (*mazenv.Maze, error) {
+	return b.GenerateRand(nil, rows, cols)
+}
+
+func (b *braidGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*mazenv.Maze, error) {
+	maze, err := b.Generator.GenerateRand(rng, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	mazenv.Braid(maze, b.Fraction, rng)
+	return maze, nil
+}
+
 var Generators = map[string]Generator{
-	"prim":   &mazenv.PrimGenerator{},
-	"island": &mazenv.IslandGenerator{},
+	"prim":          &mazenv.PrimGenerator{},
+	"island":        &mazenv.IslandGenerator{},
+	"backtracker":   &mazenv.BacktrackerGenerator{},
+	"wilson":        &mazenv.WilsonGenerator{},
+	"aldous-broder": &mazenv.AldousBroderGenerator{},
+	"kruskal":       &mazenv.KruskalGenerator{},
+	"eller":         &mazenv.EllerGenerator{},
+	"sidewinder":    &mazenv.SidewinderGenerator{},
+	"binary-tree":   &mazenv.BinaryTreeGenerator{},
+	"division":      &mazenv.DivisionGenerator{},
+	"cave":          &mazenv.CaveGenerator{},
+	"dungeon":       &mazenv.DungeonGenerator{},
 }
 
 func main() {
@@ -50,22 +183,96 @@ func main() {
 		common.AddFlags(fs)
 		algo.AddFlags(fs)
 		fs.Parse(args)
+		switch common.Format {
+		case "text", "json", "jsonl", "binary":
+		default:
+			essentials.Die("unknown format: " + common.Format)
+		}
+		textOpts, err := textOptions(common.Style)
+		if err != nil {
+			essentials.Die(err)
+		}
+		seed := int64(common.Seed)
 		if common.Seed == -1 {
-			rand.Seed(time.Now().UnixNano())
-		} else {
-			rand.Seed(int64(common.Seed))
+			seed = time.Now().UnixNano()
+		}
+		rng := rand.New(rand.NewSource(seed))
+		gen, err := common.Wrap(algo)
+		if err != nil {
+			essentials.Die(err)
+		}
+		params := map[string]string{}
+		fs.VisitAll(func(f *flag.Flag) {
+			switch f.Name {
+			case "seed", "num", "format":
+			default:
+				params[f.Name] = f.Value.String()
+			}
+		})
+		dataset := mazenv.NewDatasetWriter(os.Stdout)
+		var binWriter *mazenv.BinaryWriter
+		if common.Format == "binary" {
+			binWriter, err = mazenv.NewBinaryWriter(os.Stdout)
+			if err != nil {
+				essentials.Die(err)
+			}
 		}
+		seen := map[[32]byte]bool{}
+		var duplicates int
 		for i := 0; i < common.Num; i++ {
-			maze, err := algo.Generate(common.Rows, common.Cols)
+			// Every maze gets its own seed so that it can be
+			// reproduced without generating the others.
+			mazeSeed := rng.Int63()
+			mazeRng := rand.New(rand.NewSource(mazeSeed))
+			maze, err := gen.GenerateRand(mazeRng, common.Rows, common.Cols)
 			if err != nil {
 				essentials.Die(err)
 			}
 			if common.Border {
 				maze = maze.Bordered()
 			}
-			fmt.Println(maze.String())
-			if i+1 < common.Num {
-				fmt.Println()
+			if common.Dedupe {
+				hash, err := maze.CanonicalHash()
+				if err != nil {
+					essentials.Die(err)
+				}
+				if seen[hash] {
+					duplicates++
+					if duplicates > maxDuplicates {
+						essentials.Die("too many duplicates; only generated", i, "unique mazes")
+					}
+					i--
+					continue
+				}
+				seen[hash] = true
+				duplicates = 0
+			}
+			switch common.Format {
+			case "text":
+				fmt.Println(maze.Text(textOpts))
+				if i+1 < common.Num {
+					fmt.Println()
+				}
+			case "json":
+				data, err := json.Marshal(maze)
+				if err != nil {
+					essentials.Die(err)
+				}
+				fmt.Println(string(data))
+			case "jsonl":
+				entry := mazenv.NewDatasetEntry(maze, algoName, params, mazeSeed)
+				if err := dataset.Write(entry); err != nil {
+					essentials.Die(err)
+				}
+			case "binary":
+				if err := binWriter.Write(maze); err != nil {
+					essentials.Die(err)
+				}
+			}
+		}
+		if binWriter != nil {
+			if err := binWriter.Close(); err != nil {
+				essentials.Die(err)
 			}
 		}
 	} else {
@@ -74,6 +281,19 @@ func main() {
 	}
 }
 
+func textOptions(style string) (*mazenv.TextOptions, error) {
+	switch style {
+	case "plain":
+		return &mazenv.TextOptions{}, nil
+	case "block":
+		return &mazenv.TextOptions{Block: true}, nil
+	case "ansi":
+		return &mazenv.TextOptions{Block: true, Color: true}, nil
+	default:
+		return nil, errors.New("unknown style: " + style)
+	}
+}
+
 func dieUsage() {
 	lines := []string{
 		"Usage: generate-maze <generator> [flags | -help]",
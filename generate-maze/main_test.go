@@ -0,0 +1,70 @@
+package main
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/unixpickle/mazenv"
+)
+
+func TestCommonFlagsWrapSymmetry(t *testing.T) {
+	invalid := map[string]CommonFlags{
+		"Mask":       {Mask: "mask.txt"},
+		"MinLength":  {MinLength: 5},
+		"MaxLength":  {MaxLength: 5},
+		"Farthest":   {Farthest: true},
+		"Percentile": {Percentile: 0.5},
+	}
+	for name, flags := range invalid {
+		flags.Rows, flags.Cols = 11, 11
+		flags.Symmetry = "horizontal"
+		if _, err := flags.Wrap(&mazenv.PrimGenerator{}); err == nil {
+			t.Errorf("%s: expected error when combined with symmetry", name)
+		}
+	}
+
+	flags := CommonFlags{Rows: 11, Cols: 11, Symmetry: "horizontal", Braid: 0.5}
+	gen, err := flags.Wrap(&mazenv.PrimGenerator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	maze, err := gen.Generate(flags.Rows, flags.Cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maze.End != (mazenv.Position{Row: maze.Start.Row, Col: 10 - maze.Start.Col}) {
+		t.Errorf("asymmetric start %v and end %v", maze.Start, maze.End)
+	}
+}
+
+func TestCommonFlagsWrapMaskBraid(t *testing.T) {
+	// A masked-out column splits most of the grid in two.
+	var rows []string
+	for i := 0; i < 10; i++ {
+		rows = append(rows, ".....w.....")
+	}
+	rows = append(rows, "...........")
+	maskPath := filepath.Join(t.TempDir(), "mask.txt")
+	if err := ioutil.WriteFile(maskPath, []byte(strings.Join(rows, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	flags := CommonFlags{Rows: 11, Cols: 11, Mask: maskPath, Braid: 1}
+	gen, err := flags.Wrap(&mazenv.PrimGenerator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		maze, err := gen.GenerateRand(rand.New(rand.NewSource(seed)), flags.Rows, flags.Cols)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for row := 0; row < maze.Rows-1; row++ {
+			if !maze.Wall(mazenv.Position{Row: row, Col: 5}) {
+				t.Fatalf("seed %d: space outside of mask: %#v", seed, maze.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package mazenv
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+)
+
+// Directions on a hexagonal grid.
+const (
+	HexEast = iota
+	HexNorthEast
+	HexNorthWest
+	HexWest
+	HexSouthWest
+	HexSouthEast
+)
+
+var hexOffsets = [6]HexPosition{
+	HexEast:      {Q: 1, R: 0},
+	HexNorthEast: {Q: 1, R: -1},
+	HexNorthWest: {Q: 0, R: -1},
+	HexWest:      {Q: -1, R: 0},
+	HexSouthWest: {Q: -1, R: 1},
+	HexSouthEast: {Q: 0, R: 1},
+}
+
+// HexPosition represents a place on a HexMaze in axial
+// coordinates.
+//
+// Moving east increases Q, and moving south-east
+// increases R.
+type HexPosition struct {
+	Q int
+	R int
+}
+
+// Neighbor gets the adjacent position in the direction,
+// such as HexEast.
+func (h HexPosition) Neighbor(dir int) HexPosition {
+	offset := hexOffsets[dir]
+	return HexPosition{Q: h.Q + offset.Q, R: h.R + offset.R}
+}
+
+// HexMaze defines a maze of hexagonal cells.
+//
+// The cells form a parallelogram with Rows rows of Cols
+// cells each, where each row is shifted half a cell east
+// of the one above it.
+// Unlike Maze, every cell is a space, and walls are the
+// edges between cells without a passage.
+type HexMaze struct {
+	Rows int
+	Cols int
+
+	Start HexPosition
+	End   HexPosition
+
+	// Passages is a row-major list of bitmasks specifying
+	// the passages out of each cell.
+	// Bit d is set if there is a passage in direction d.
+	//
+	// Passages should be symmetric, so use SetOpen rather
+	// than modifying this directly.
+	Passages []uint8
+}
+
+// NewHexMaze creates a maze with no passages.
+func NewHexMaze(rows, cols int) *HexMaze {
+	return &HexMaze{
+		Rows:     rows,
+		Cols:     cols,
+		Passages: make([]uint8, rows*cols),
+	}
+}
+
+// ParseHexMaze parses a maze from a string.
+// See String for details on the format.
+func ParseHexMaze(s string) (maze *HexMaze, err error) {
+	defer essentials.AddCtxTo("parse hex maze", &err)
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines[0])%2 != 0 {
+		return nil, errors.New("odd number of characters in row")
+	}
+	maze = NewHexMaze(len(lines), len(lines[0])/2)
+	var seenStart, seenEnd bool
+	for row, line := range lines {
+		if len(line) != maze.Cols*2 {
+			return nil, errors.New("inconsistent number of columns")
+		}
+		for col := 0; col < maze.Cols; col++ {
+			pos := HexPosition{Q: col, R: row}
+			digit, marker := line[col*2], line[col*2+1]
+			if digit < '0' || digit > '7' {
+				return nil, errors.New("invalid passage digit: " + string(digit))
+			}
+			for i, dir := range []int{HexEast, HexSouthEast, HexSouthWest} {
+				if (digit-'0')&(1<<uint(i)) == 0 {
+					continue
+				}
+				if !maze.InBounds(pos.Neighbor(dir)) {
+					return nil, errors.New("passage out of bounds")
+				}
+				maze.SetOpen(pos, dir, true)
+			}
+			switch marker {
+			case '.':
+			case 'A':
+				if seenStart {
+					return nil, errors.New("multiple starts")
+				}
+				maze.Start = pos
+				seenStart = true
+			case 'x':
+				if seenEnd {
+					return nil, errors.New("multiple ends")
+				}
+				maze.End = pos
+				seenEnd = true
+			default:
+				return nil, errors.New("invalid cell marker: " + string(marker))
+			}
+		}
+	}
+	if !seenStart {
+		return nil, errors.New("missing start")
+	}
+	if !seenEnd {
+		return nil, errors.New("missing end")
+	}
+	return
+}
+
+// InBounds checks if the position is within the grid.
+func (h *HexMaze) InBounds(pos HexPosition) bool {
+	return pos.R >= 0 && pos.R < h.Rows && pos.Q >= 0 && pos.Q < h.Cols
+}
+
+// Positions returns all valid positions in the grid in
+// the same order as h.Passages.
+func (h *HexMaze) Positions() []HexPosition {
+	var res []HexPosition
+	for row := 0; row < h.Rows; row++ {
+		for col := 0; col < h.Cols; col++ {
+			res = append(res, HexPosition{Q: col, R: row})
+		}
+	}
+	return res
+}
+
+// CellIndex gets the index for the cell.
+//
+// The position must be within bounds.
+func (h *HexMaze) CellIndex(pos HexPosition) int {
+	if !h.InBounds(pos) {
+		panic("out of bounds")
+	}
+	return pos.R*h.Cols + pos.Q
+}
+
+// Open checks if there is a passage from the cell in the
+// given direction.
+//
+// If the cell is out of bounds, false is returned.
+func (h *HexMaze) Open(pos HexPosition, dir int) bool {
+	if !h.InBounds(pos) {
+		return false
+	}
+	return h.Passages[h.CellIndex(pos)]&(1<<uint(dir)) != 0
+}
+
+// SetOpen adds or removes the passage between a cell and
+// its neighbor in the given direction.
+//
+// Both cells must be within bounds.
+func (h *HexMaze) SetOpen(pos HexPosition, dir int, open bool) {
+	other := pos.Neighbor(dir)
+	otherDir := (dir + 3) % 6
+	idx1, idx2 := h.CellIndex(pos), h.CellIndex(other)
+	if open {
+		h.Passages[idx1] |= 1 << uint(dir)
+		h.Passages[idx2] |= 1 << uint(otherDir)
+	} else {
+		h.Passages[idx1] &^= 1 << uint(dir)
+		h.Passages[idx2] &^= 1 << uint(otherDir)
+	}
+}
+
+// OpenNeighbors returns the cells that can be reached in
+// one step from a cell.
+func (h *HexMaze) OpenNeighbors(pos HexPosition) []HexPosition {
+	var res []HexPosition
+	for dir := 0; dir < 6; dir++ {
+		if h.Open(pos, dir) {
+			res = append(res, pos.Neighbor(dir))
+		}
+	}
+	return res
+}
+
+// String produces a text representation of the grid.
+//
+// Each cell is represented by two characters.
+// The first is a digit whose bits indicate passages to
+// the east (1), south-east (2), and south-west (4).
+// The second is 'A' for the start, 'x' for the end, and
+// '.' otherwise.
+// Each row is separated by a newline.
+//
+// For a more visual representation, see Render.
+func (h *HexMaze) String() string {
+	rows := make([]string, h.Rows)
+	for row := 0; row < h.Rows; row++ {
+		for col := 0; col < h.Cols; col++ {
+			pos := HexPosition{Q: col, R: row}
+			var digit byte = '0'
+			for i, dir := range []int{HexEast, HexSouthEast, HexSouthWest} {
+				if h.Open(pos, dir) {
+					digit += 1 << uint(i)
+				}
+			}
+			marker := byte('.')
+			if pos == h.Start {
+				marker = 'A'
+			} else if pos == h.End {
+				marker = 'x'
+			}
+			rows[row] += string([]byte{digit, marker})
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// Render draws the maze's passages as ASCII art.
+//
+// Cells are drawn as 'o', or as 'A' and 'x' for the start
+// and end.
+// Passages are drawn as lines ('-', '/', and '\') between
+// cells, and every row is indented to show the shape of
+// the grid.
+func (h *HexMaze) Render() string {
+	if h.Rows == 0 || h.Cols == 0 {
+		return ""
+	}
+	width := 4*(h.Cols-1) + 2*(h.Rows-1) + 1
+	lines := make([][]byte, 2*h.Rows-1)
+	for i := range lines {
+		lines[i] = []byte(strings.Repeat(" ", width))
+	}
+	for _, pos := range h.Positions() {
+		row, col := 2*pos.R, 4*pos.Q+2*pos.R
+		switch pos {
+		case h.Start:
+			lines[row][col] = 'A'
+		case h.End:
+			lines[row][col] = 'x'
+		default:
+			lines[row][col] = 'o'
+		}
+		if h.Open(pos, HexEast) {
+			copy(lines[row][col+1:], "---")
+		}
+		if h.Open(pos, HexSouthEast) {
+			lines[row+1][col+1] = '\\'
+		}
+		if h.Open(pos, HexSouthWest) {
+			lines[row+1][col-1] = '/'
+		}
+	}
+	res := make([]string, len(lines))
+	for i, line := range lines {
+		res[i] = strings.TrimRight(string(line), " ")
+	}
+	return strings.Join(res, "\n")
+}
+
+// SolveHex finds an optimal solution to the maze.
+//
+// The solution is represented as a list of positions that
+// comprise the solution, including the start and end.
+//
+// If no solution is found, nil is returned.
+func SolveHex(h *HexMaze) []HexPosition {
+	parents := map[HexPosition]HexPosition{h.Start: h.Start}
+	queue := []HexPosition{h.Start}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		if pos == h.End {
+			var res []HexPosition
+			for pos != h.Start {
+				res = append(res, pos)
+				pos = parents[pos]
+			}
+			res = append(res, h.Start)
+			for i := 0; i < len(res)/2; i++ {
+				res[i], res[len(res)-1-i] = res[len(res)-1-i], res[i]
+			}
+			return res
+		}
+		for _, neighbor := range h.OpenNeighbors(pos) {
+			if _, ok := parents[neighbor]; !ok {
+				parents[neighbor] = pos
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package mazenv
+
+import (
+	"errors"
+
+	"github.com/unixpickle/anyrl"
+)
+
+// Indices in one-hot action vectors for hexagonal mazes.
+//
+// Every action besides HexActionNop is one more than the
+// corresponding direction, such as HexEast.
+const (
+	HexActionNop = iota
+	HexActionEast
+	HexActionNorthEast
+	HexActionNorthWest
+	HexActionWest
+	HexActionSouthWest
+	HexActionSouthEast
+)
+
+// HexEnv is a generic hexagonal maze environment.
+//
+// Actions are one-hot vectors with seven possibilities.
+// See HexActionNop, HexActionEast, etc.
+type HexEnv interface {
+	anyrl.Env
+
+	// Maze returns the environment's map.
+	Maze() *HexMaze
+
+	// Position returns the player's current position.
+	Position() HexPosition
+}
+
+// rawHexEnv is a barebones environment for a hex maze.
+type rawHexEnv struct {
+	maze     *HexMaze
+	position HexPosition
+}
+
+// NewHexEnv creates a HexEnv for the maze.
+//
+// Observations are row-major representations of the
+// maze grid.
+// Each cell is represented as a boolean (is current
+// position), followed by six booleans indicating passages
+// in each direction, followed by two booleans (is start,
+// is end).
+//
+// Rewards are -1 until the maze is solved, at which point
+// the episode ends and the reward is 0.
+func NewHexEnv(maze *HexMaze) HexEnv {
+	return &rawHexEnv{maze: maze}
+}
+
+// Maze returns the maze.
+func (r *rawHexEnv) Maze() *HexMaze {
+	return r.maze
+}
+
+// Position returns the current position.
+func (r *rawHexEnv) Position() HexPosition {
+	return r.position
+}
+
+// Reset resets the player's position to the start.
+func (r *rawHexEnv) Reset() (obs []float64, err error) {
+	r.position = r.maze.Start
+	return r.observation(), nil
+}
+
+// Step takes a step in the environment.
+func (r *rawHexEnv) Step(action []float64) (obs []float64, reward float64,
+	done bool, err error) {
+	if r.position == r.maze.End {
+		err = errors.New("step: maze is already solved")
+		return
+	}
+	var actionIdx int
+	for i, x := range action {
+		if x != 0 {
+			actionIdx = i
+		}
+	}
+	if actionIdx != HexActionNop {
+		dir := actionIdx - 1
+		if r.maze.Open(r.position, dir) {
+			r.position = r.position.Neighbor(dir)
+		}
+	}
+	if r.position == r.maze.End {
+		reward = 0
+		done = true
+	} else {
+		reward = -1
+	}
+	obs = r.observation()
+	return
+}
+
+func (r *rawHexEnv) observation() []float64 {
+	var res []float64
+	for _, pos := range r.maze.Positions() {
+		res = append(res, boolToFloat(pos == r.position))
+		for dir := 0; dir < 6; dir++ {
+			res = append(res, boolToFloat(r.maze.Open(pos, dir)))
+		}
+		res = append(res, boolToFloat(pos == r.maze.Start), boolToFloat(pos == r.maze.End))
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package mazenv
+
+import "testing"
+
+func TestHexEnv(t *testing.T) {
+	env := NewHexEnv(testingHexMaze())
+
+	obs, err := env.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedInitial := []float64{
+		0, 1, 0, 0, 0, 0, 1, 0, 0,
+		0, 0, 0, 0, 1, 0, 1, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0,
+
+		1, 0, 0, 1, 0, 0, 0, 1, 0,
+		0, 1, 0, 1, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 1, 0, 0, 0, 1,
+	}
+	testObsEqual(t, obs, expectedInitial)
+
+	for _, act := range []int{HexActionNop, HexActionEast, HexActionWest, HexActionSouthEast} {
+		obs, reward, done, err := env.Step(oneHotHexAction(act))
+		testNotDoneStepResult(t, reward, done, err)
+		testObsEqual(t, obs, expectedInitial)
+	}
+
+	for _, act := range []int{HexActionNorthWest, HexActionEast, HexActionSouthEast} {
+		_, reward, done, err := env.Step(oneHotHexAction(act))
+		testNotDoneStepResult(t, reward, done, err)
+	}
+	if env.Position() != (HexPosition{Q: 1, R: 1}) {
+		t.Fatalf("unexpected position: %v", env.Position())
+	}
+
+	obs, reward, done, err := env.Step(oneHotHexAction(HexActionEast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || reward != 0 {
+		t.Error("expected episode to end with reward 0")
+	}
+	if obs[5*9] != 1 || obs[3*9] != 0 {
+		t.Error("unexpected position in observation")
+	}
+
+	if _, _, _, err := env.Step(oneHotHexAction(HexActionWest)); err == nil {
+		t.Error("expected error from step after end of episode")
+	}
+}
+
+func oneHotHexAction(idx int) []float64 {
+	data := make([]float64, 7)
+	data[idx] = 1
+	return data
+}
@@ -0,0 +1,168 @@
+package mazenv
+
+import (
+	"errors"
+	"flag"
+	"math/rand"
+
+	"github.com/unixpickle/essentials"
+)
+
+// A HexGenerator generates hexagonal mazes.
+type HexGenerator interface {
+	Generate(rows, cols int) (*HexMaze, error)
+	GenerateRand(rng *rand.Rand, rows, cols int) (*HexMaze, error)
+}
+
+// HexPrimGenerator is a HexGenerator that uses a
+// randomized variant of Prim's algorithm.
+type HexPrimGenerator struct{}
+
+// Description returns a short description of what the
+// algorithm does.
+func (h *HexPrimGenerator) Description() string {
+	return "randomized variant of Prim's algorithm (hexagonal)"
+}
+
+// AddFlags adds the generator's options as flags.
+//
+// Currently, this is a no-op.
+func (h *HexPrimGenerator) AddFlags(fs *flag.FlagSet) {
+}
+
+// Generate generates a random maze.
+func (h *HexPrimGenerator) Generate(rows, cols int) (*HexMaze, error) {
+	return h.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (h *HexPrimGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*HexMaze, error) {
+	rng = randOrGlobal(rng)
+	if rows*cols < 2 {
+		return nil, errors.New("not enough cells")
+	}
+	maze := NewHexMaze(rows, cols)
+
+	type edge struct {
+		Pos HexPosition
+		Dir int
+	}
+	visited := make([]bool, rows*cols)
+	var edges []edge
+	visit := func(pos HexPosition) {
+		visited[maze.CellIndex(pos)] = true
+		for dir := 0; dir < 6; dir++ {
+			if maze.InBounds(pos.Neighbor(dir)) {
+				edges = append(edges, edge{Pos: pos, Dir: dir})
+			}
+		}
+	}
+	visit(HexPosition{Q: rng.Intn(cols), R: rng.Intn(rows)})
+
+	for len(edges) > 0 {
+		idx := rng.Intn(len(edges))
+		e := edges[idx]
+		essentials.UnorderedDelete(&edges, idx)
+		next := e.Pos.Neighbor(e.Dir)
+		if !visited[maze.CellIndex(next)] {
+			maze.SetOpen(e.Pos, e.Dir, true)
+			visit(next)
+		}
+	}
+
+	placeHexStartEnd(rng, maze)
+	return maze, nil
+}
+
+// HexBacktrackerGenerator is a HexGenerator that uses a
+// randomized depth-first search.
+//
+// Mazes from this generator tend to have long, winding
+// corridors with few branches.
+type HexBacktrackerGenerator struct {
+	// Straightness is the probability of continuing a
+	// corridor in the same direction when possible.
+	// The value may range from 0 to 1.
+	Straightness float64
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (h *HexBacktrackerGenerator) Description() string {
+	return "randomized depth-first search (hexagonal)"
+}
+
+// AddFlags adds the generator's options as flags.
+func (h *HexBacktrackerGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&h.Straightness, "straightness", 0,
+		"chance of continuing a corridor in the same direction")
+}
+
+// Generate generates a random maze.
+func (h *HexBacktrackerGenerator) Generate(rows, cols int) (*HexMaze, error) {
+	return h.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (h *HexBacktrackerGenerator) GenerateRand(rng *rand.Rand, rows,
+	cols int) (*HexMaze, error) {
+	rng = randOrGlobal(rng)
+	if rows*cols < 2 {
+		return nil, errors.New("not enough cells")
+	}
+	maze := NewHexMaze(rows, cols)
+
+	type stackEntry struct {
+		Pos HexPosition
+		Dir int
+	}
+	first := HexPosition{Q: rng.Intn(cols), R: rng.Intn(rows)}
+	visited := make([]bool, rows*cols)
+	visited[maze.CellIndex(first)] = true
+	stack := []stackEntry{{Pos: first, Dir: -1}}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		var options []int
+		for dir := 0; dir < 6; dir++ {
+			next := cur.Pos.Neighbor(dir)
+			if maze.InBounds(next) && !visited[maze.CellIndex(next)] {
+				options = append(options, dir)
+			}
+		}
+		if len(options) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		dir := options[rng.Intn(len(options))]
+		if cur.Dir != -1 && rng.Float64() < h.Straightness {
+			for _, d := range options {
+				if d == cur.Dir {
+					dir = d
+				}
+			}
+		}
+		next := cur.Pos.Neighbor(dir)
+		maze.SetOpen(cur.Pos, dir, true)
+		visited[maze.CellIndex(next)] = true
+		stack = append(stack, stackEntry{Pos: next, Dir: dir})
+	}
+
+	placeHexStartEnd(rng, maze)
+	return maze, nil
+}
+
+// placeHexStartEnd picks distinct, random cells for the
+// start and end of a maze.
+//
+// The maze must have at least two cells.
+func placeHexStartEnd(rng *rand.Rand, h *HexMaze) {
+	perm := rng.Perm(h.Rows * h.Cols)
+	h.Start = HexPosition{Q: perm[0] % h.Cols, R: perm[0] / h.Cols}
+	h.End = HexPosition{Q: perm[1] % h.Cols, R: perm[1] / h.Cols}
+}
@@ -0,0 +1,40 @@
+package mazenv
+
+import "testing"
+
+func TestHexGenerators(t *testing.T) {
+	gens := map[string]HexGenerator{
+		"HexPrimGenerator":        &HexPrimGenerator{},
+		"HexBacktrackerGenerator": &HexBacktrackerGenerator{Straightness: 0.5},
+	}
+	for name, gen := range gens {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				m, err := gen.Generate(8, 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if m.Rows != 8 || m.Cols != 10 || len(m.Passages) != 80 {
+					t.Fatal("invalid dimensions")
+				}
+				if m.Start == m.End {
+					t.Error("overlapping start and end")
+				}
+
+				var numEdges int
+				for _, p := range m.Positions() {
+					numEdges += len(m.OpenNeighbors(p))
+				}
+				if numEdges/2 != 79 {
+					t.Errorf("expected 79 passages but got %d", numEdges/2)
+				}
+				for _, p := range m.Positions() {
+					m.End = p
+					if p != m.Start && SolveHex(m) == nil {
+						t.Fatalf("unreachable cell: %v", p)
+					}
+				}
+			}
+		})
+	}
+}
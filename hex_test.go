@@ -0,0 +1,68 @@
+package mazenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHexMazeString(t *testing.T) {
+	maze := testingHexMaze()
+	actual := maze.String()
+	expected := "3.2.0.\n0A1.0x"
+	if actual != expected {
+		t.Errorf("expected %#v but got %#v", expected, actual)
+	}
+
+	shouldFail := []string{"0A0x0", "0A0x\n0.", "0A0.", "0x0.", "0A0A0x", "0A1x", "0A8x", "0A0?"}
+	for _, s := range shouldFail {
+		if _, err := ParseHexMaze(s); err == nil {
+			t.Errorf("expected failure for %#v", s)
+		}
+	}
+}
+
+func TestHexMazeParse(t *testing.T) {
+	expected := testingHexMaze()
+	actual, err := ParseHexMaze(expected.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v but got %#v", expected, actual)
+	}
+}
+
+func TestHexMazeRender(t *testing.T) {
+	actual := testingHexMaze().Render()
+	expected := "o---o   o\n" +
+		" \\   \\\n" +
+		"  A   o---x"
+	if actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestSolveHex(t *testing.T) {
+	maze := testingHexMaze()
+	actual := SolveHex(maze)
+	expected := []HexPosition{{0, 1}, {0, 0}, {1, 0}, {1, 1}, {2, 1}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+
+	maze.End = HexPosition{Q: 2, R: 0}
+	if SolveHex(maze) != nil {
+		t.Error("found false solution")
+	}
+}
+
+func testingHexMaze() *HexMaze {
+	maze := NewHexMaze(2, 3)
+	maze.SetOpen(HexPosition{0, 0}, HexEast, true)
+	maze.SetOpen(HexPosition{0, 0}, HexSouthEast, true)
+	maze.SetOpen(HexPosition{1, 0}, HexSouthEast, true)
+	maze.SetOpen(HexPosition{1, 1}, HexEast, true)
+	maze.Start = HexPosition{0, 1}
+	maze.End = HexPosition{2, 1}
+	return maze
+}
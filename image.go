@@ -0,0 +1,209 @@
+package mazenv
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// ImageOptions controls how mazes are read from images.
+type ImageOptions struct {
+	// CellSize is the width and height of each cell in
+	// pixels.
+	//
+	// If 0, the cell size is detected from the lengths of
+	// runs of wall and space pixels.
+	CellSize int
+
+	// Threshold is the luminance, from 0 to 1, below which
+	// pixels are considered walls.
+	//
+	// If 0, a default of 0.5 is used.
+	Threshold float64
+
+	// Start and End, if non-nil, override the start and
+	// end positions.
+	// Otherwise, the start is the cell colored green and
+	// the end is the cell colored red.
+	Start *Position
+	End   *Position
+}
+
+// Pixel classes for image parsing.
+const (
+	pixelSpace = iota
+	pixelWall
+	pixelStart
+	pixelEnd
+)
+
+// ReadMazeImage decodes a PNG or JPEG image and parses a
+// maze from it with ParseMazeImage.
+func ReadMazeImage(r io.Reader, opts *ImageOptions) (*Maze, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, essentials.AddCtx("read maze image", err)
+	}
+	return ParseMazeImage(img, opts)
+}
+
+// ParseMazeImage parses a maze from an image of a grid.
+//
+// Dark gray pixels are walls, while light, transparent,
+// or brightly colored pixels are spaces.
+// Each cell takes the majority class of the pixels near
+// its center, except that a cell is the start (or end)
+// if at least a quarter of those pixels are green (or
+// red).
+//
+// If there is not exactly one start and one end, the
+// same errors as ParseMaze are returned, such as
+// ErrMissingStart.
+//
+// The options may be nil to use the defaults.
+func ParseMazeImage(img image.Image, opts *ImageOptions) (*Maze, error) {
+	if opts == nil {
+		opts = &ImageOptions{}
+	}
+	threshold := opts.Threshold
+	if threshold == 0 {
+		threshold = 0.5
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, errors.New("parse maze image: empty image")
+	}
+	pixels := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixels[y*width+x] = classifyPixel(img.At(bounds.Min.X+x, bounds.Min.Y+y),
+				threshold)
+		}
+	}
+
+	cellSize := opts.CellSize
+	if cellSize == 0 {
+		cellSize = detectCellSize(pixels, width, height)
+	}
+	maze := &Maze{
+		Rows: essentials.MaxInt(1, int(math.Round(float64(height)/float64(cellSize)))),
+		Cols: essentials.MaxInt(1, int(math.Round(float64(width)/float64(cellSize)))),
+	}
+	maze.Walls = make([]bool, maze.Rows*maze.Cols)
+
+	var starts, ends []Position
+	for _, p := range maze.Positions() {
+		var counts [4]int
+		x0, y0 := p.Col*cellSize+cellSize/4, p.Row*cellSize+cellSize/4
+		x1, y1 := p.Col*cellSize+(3*cellSize+3)/4, p.Row*cellSize+(3*cellSize+3)/4
+		for y := y0; y < y1 && y < height; y++ {
+			for x := x0; x < x1 && x < width; x++ {
+				counts[pixels[y*width+x]]++
+			}
+		}
+		total := counts[0] + counts[1] + counts[2] + counts[3]
+		if opts.Start == nil && counts[pixelStart]*4 >= total && total > 0 {
+			starts = append(starts, p)
+		} else if opts.End == nil && counts[pixelEnd]*4 >= total && total > 0 {
+			ends = append(ends, p)
+		} else if counts[pixelWall]*2 > total {
+			maze.Walls[maze.CellIndex(p)] = true
+		}
+	}
+
+	if opts.Start != nil {
+		starts = []Position{*opts.Start}
+	}
+	if opts.End != nil {
+		ends = []Position{*opts.End}
+	}
+	if len(starts) == 0 {
+		return nil, ErrMissingStart
+	} else if len(starts) > 1 {
+		return nil, ErrMultipleStarts
+	} else if len(ends) == 0 {
+		return nil, ErrMissingEnd
+	} else if len(ends) > 1 {
+		return nil, ErrMultipleEnds
+	}
+	maze.Start, maze.End = starts[0], ends[0]
+	for _, p := range []Position{maze.Start, maze.End} {
+		if !maze.InBounds(p) {
+			return nil, errors.New("parse maze image: start or end out of bounds")
+		}
+		maze.Walls[maze.CellIndex(p)] = false
+	}
+	return maze, nil
+}
+
+func classifyPixel(c color.Color, threshold float64) int {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if nrgba.A < 0x80 {
+		return pixelSpace
+	}
+	r, g, b := float64(nrgba.R)/0xff, float64(nrgba.G)/0xff, float64(nrgba.B)/0xff
+	if g-math.Max(r, b) > 0.25 {
+		return pixelStart
+	} else if r-math.Max(g, b) > 0.25 {
+		return pixelEnd
+	} else if math.Max(r, math.Max(g, b))-math.Min(r, math.Min(g, b)) > 0.25 {
+		// Other saturated colors, such as drawn paths, are
+		// never walls.
+		return pixelSpace
+	} else if 0.299*r+0.587*g+0.114*b < threshold {
+		return pixelWall
+	}
+	return pixelSpace
+}
+
+// detectCellSize finds the largest size such that almost
+// every run of wall or non-wall pixels, along both rows
+// and columns, is close to a multiple of the size.
+//
+// Small tolerances make this robust to anti-aliasing and
+// compression artifacts, unlike an exact GCD.
+func detectCellSize(pixels []int, width, height int) int {
+	runs := map[int]int{}
+	countRuns := func(n, length int, wall func(i, j int) bool) {
+		for i := 0; i < n; i++ {
+			start := 0
+			for j := 1; j <= length; j++ {
+				if j == length || wall(i, j) != wall(i, start) {
+					runs[j-start]++
+					start = j
+				}
+			}
+		}
+	}
+	countRuns(height, width, func(y, x int) bool {
+		return pixels[y*width+x] == pixelWall
+	})
+	countRuns(width, height, func(x, y int) bool {
+		return pixels[y*width+x] == pixelWall
+	})
+
+	var totalWeight int
+	for length, count := range runs {
+		totalWeight += length * count
+	}
+	for size := essentials.MinInt(width, height); size > 1; size-- {
+		tolerance := size / 5
+		var fitWeight int
+		for length, count := range runs {
+			multiple := essentials.MaxInt(1, int(math.Round(float64(length)/float64(size))))
+			if diff := length - multiple*size; diff >= -tolerance && diff <= tolerance {
+				fitWeight += length * count
+			}
+		}
+		if fitWeight*100 >= totalWeight*95 {
+			return size
+		}
+	}
+	return 1
+}
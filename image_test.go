@@ -0,0 +1,117 @@
+package mazenv
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestParseMazeImage(t *testing.T) {
+	for i := int64(0); i < 3; i++ {
+		expected, err := GenerateSeed(&PrimGenerator{}, i, 15, 21)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img := testingMazeImage(expected, 7)
+
+		actual, err := ParseMazeImage(img, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual.String() != expected.String() {
+			t.Errorf("expected %#v but got %#v", expected.String(), actual.String())
+		}
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 75}); err != nil {
+			t.Fatal(err)
+		}
+		actual, err = ReadMazeImage(&buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual.String() != expected.String() {
+			t.Errorf("JPEG: expected %#v but got %#v", expected.String(), actual.String())
+		}
+	}
+}
+
+func TestParseMazeImageOptions(t *testing.T) {
+	maze, err := ParseMaze("A..\nww.\nx..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := testingMazeImage(maze, 4)
+	start, end := Position{Row: 0, Col: 2}, Position{Row: 2, Col: 2}
+	actual, err := ParseMazeImage(img, &ImageOptions{CellSize: 4, Start: &start, End: &end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "..A\nww.\n..x"; actual.String() != expected {
+		t.Errorf("expected %#v but got %#v", expected, actual.String())
+	}
+}
+
+func TestParseMazeImageErrors(t *testing.T) {
+	cases := map[string]error{
+		"...\nww.\nx..": ErrMissingStart,
+		"A..\nww.\n...": ErrMissingEnd,
+		"A.A\nww.\nx..": ErrMultipleStarts,
+		"A..\nwwx\nx..": ErrMultipleEnds,
+	}
+	for s, expected := range cases {
+		if _, err := ParseMaze(s); err != expected {
+			t.Errorf("ParseMaze(%#v): expected %v but got %v", s, expected, err)
+		}
+
+		// Draw the maze with a placeholder for the missing
+		// start or end, then color the cells by hand.
+		maze := &Maze{Rows: 3, Cols: 3, Walls: make([]bool, 9), Start: Position{-1, -1},
+			End: Position{-1, -1}}
+		img := testingMazeImage(maze, 6)
+		for i, ch := range []rune(s) {
+			pos := Position{Row: i / 4, Col: i % 4}
+			var c color.Color
+			switch ch {
+			case 'w':
+				c = color.Black
+			case 'A':
+				c = color.RGBA{G: 0xff, A: 0xff}
+			case 'x':
+				c = color.RGBA{R: 0xff, A: 0xff}
+			default:
+				continue
+			}
+			fillTestingCell(img, pos, 6, c)
+		}
+		if _, err := ParseMazeImage(img, &ImageOptions{CellSize: 6}); err != expected {
+			t.Errorf("ParseMazeImage(%#v): expected %v but got %v", s, expected, err)
+		}
+	}
+}
+
+func testingMazeImage(m *Maze, cellSize int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, m.Cols*cellSize, m.Rows*cellSize))
+	for _, p := range m.Positions() {
+		c := color.Color(color.White)
+		if p == m.Start {
+			c = color.RGBA{G: 0xc0, A: 0xff}
+		} else if p == m.End {
+			c = color.RGBA{R: 0xe0, A: 0xff}
+		} else if m.Wall(p) {
+			c = color.Black
+		}
+		fillTestingCell(img, p, cellSize, c)
+	}
+	return img
+}
+
+func fillTestingCell(img *image.RGBA, p Position, cellSize int, c color.Color) {
+	for y := p.Row * cellSize; y < (p.Row+1)*cellSize; y++ {
+		for x := p.Col * cellSize; x < (p.Col+1)*cellSize; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
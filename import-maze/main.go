@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/mazenv"
+)
+
+func main() {
+	var imagePath string
+	var startStr, endStr string
+	var opts mazenv.ImageOptions
+	flag.StringVar(&imagePath, "in", "", "PNG or JPEG image of a maze (instead of stdin)")
+	flag.IntVar(&opts.CellSize, "cell", 0, "cell size in pixels (0 to detect)")
+	flag.Float64Var(&opts.Threshold, "threshold", 0.5, "luminance (0 to 1) below which pixels are walls")
+	flag.StringVar(&startStr, "start", "", "start position as 'row,col' (instead of green pixels)")
+	flag.StringVar(&endStr, "end", "", "end position as 'row,col' (instead of red pixels)")
+	flag.Parse()
+
+	var err error
+	if opts.Start, err = parsePosition(startStr); err != nil {
+		essentials.Die(err)
+	}
+	if opts.End, err = parsePosition(endStr); err != nil {
+		essentials.Die(err)
+	}
+
+	var r io.Reader = os.Stdin
+	if imagePath == "" {
+		fmt.Fprintln(os.Stderr, "reading from standard input...")
+	} else {
+		f, err := os.Open(imagePath)
+		if err != nil {
+			essentials.Die(err)
+		}
+		defer f.Close()
+		r = f
+	}
+	maze, err := mazenv.ReadMazeImage(r, &opts)
+	if err != nil {
+		essentials.Die(err)
+	}
+	fmt.Println(maze.String())
+}
+
+func parsePosition(s string) (*mazenv.Position, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, errors.New("invalid position: " + s)
+	}
+	row, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	col, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil {
+		return nil, errors.New("invalid position: " + s)
+	}
+	return &mazenv.Position{Row: row, Col: col}, nil
+}
@@ -0,0 +1,170 @@
+package mazenv
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+)
+
+// jsonMaze is the JSON representation of a Maze.
+type jsonMaze struct {
+	Rows  int      `json:"rows"`
+	Cols  int      `json:"cols"`
+	Start [2]int   `json:"start"`
+	End   [2]int   `json:"end"`
+	Walls []string `json:"walls"`
+}
+
+// MarshalJSON encodes the maze as a JSON object.
+//
+// The object stores the dimensions, the start and end as
+// [row, col] pairs, and the walls as a list of rows.
+// Each row is a string with a 'w' for every wall and a '.'
+// for every space.
+func (m *Maze) MarshalJSON() ([]byte, error) {
+	obj := jsonMaze{
+		Rows:  m.Rows,
+		Cols:  m.Cols,
+		Start: [2]int{m.Start.Row, m.Start.Col},
+		End:   [2]int{m.End.Row, m.End.Col},
+		Walls: make([]string, m.Rows),
+	}
+	for row := range obj.Walls {
+		var line strings.Builder
+		for col := 0; col < m.Cols; col++ {
+			if m.Wall(Position{Row: row, Col: col}) {
+				line.WriteByte('w')
+			} else {
+				line.WriteByte('.')
+			}
+		}
+		obj.Walls[row] = line.String()
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON decodes a maze that was encoded with
+// MarshalJSON.
+func (m *Maze) UnmarshalJSON(data []byte) (err error) {
+	defer essentials.AddCtxTo("unmarshal maze", &err)
+	var obj jsonMaze
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if obj.Rows < 0 || obj.Cols < 0 {
+		return errors.New("negative dimensions")
+	}
+	if len(obj.Walls) != obj.Rows {
+		return errors.New("inconsistent number of rows")
+	}
+	res := Maze{
+		Rows:  obj.Rows,
+		Cols:  obj.Cols,
+		Start: Position{Row: obj.Start[0], Col: obj.Start[1]},
+		End:   Position{Row: obj.End[0], Col: obj.End[1]},
+		Walls: make([]bool, 0, obj.Rows*obj.Cols),
+	}
+	for _, line := range obj.Walls {
+		if len(line) != obj.Cols {
+			return errors.New("inconsistent number of columns")
+		}
+		for _, ch := range line {
+			switch ch {
+			case 'w':
+				res.Walls = append(res.Walls, true)
+			case '.':
+				res.Walls = append(res.Walls, false)
+			default:
+				return errors.New("unknown cell: " + string(ch))
+			}
+		}
+	}
+	*m = res
+	return nil
+}
+
+// DatasetEntry is a maze along with metadata describing
+// how it was created.
+//
+// Datasets are stored in the JSON Lines format, with one
+// JSON-encoded DatasetEntry per line.
+type DatasetEntry struct {
+	Maze *Maze `json:"maze"`
+
+	// Generator is the name of the generator which
+	// produced the maze.
+	Generator string `json:"generator,omitempty"`
+
+	// Params stores the generator's options.
+	Params map[string]string `json:"params,omitempty"`
+
+	// Seed is the seed which produced the maze, for
+	// example via GenerateSeed.
+	Seed int64 `json:"seed"`
+
+	// OptimalLength is the number of steps in the shortest
+	// solution, or -1 if the maze is unsolvable.
+	OptimalLength int `json:"optimal_length"`
+}
+
+// NewDatasetEntry creates a DatasetEntry for a maze and
+// computes its OptimalLength.
+func NewDatasetEntry(m *Maze, generator string, params map[string]string,
+	seed int64) *DatasetEntry {
+	return &DatasetEntry{
+		Maze:          m,
+		Generator:     generator,
+		Params:        params,
+		Seed:          seed,
+		OptimalLength: len(Solve(m)) - 1,
+	}
+}
+
+// DatasetWriter writes DatasetEntries in the JSON Lines
+// format.
+type DatasetWriter struct {
+	enc *json.Encoder
+}
+
+// NewDatasetWriter creates a DatasetWriter that writes
+// to w.
+func NewDatasetWriter(w io.Writer) *DatasetWriter {
+	return &DatasetWriter{enc: json.NewEncoder(w)}
+}
+
+// Write writes an entry on its own line.
+func (d *DatasetWriter) Write(e *DatasetEntry) error {
+	return d.enc.Encode(e)
+}
+
+// DatasetReader reads DatasetEntries in the JSON Lines
+// format.
+type DatasetReader struct {
+	dec *json.Decoder
+}
+
+// NewDatasetReader creates a DatasetReader that reads
+// from r.
+func NewDatasetReader(r io.Reader) *DatasetReader {
+	return &DatasetReader{dec: json.NewDecoder(r)}
+}
+
+// Read reads the next entry.
+//
+// At the end of the stream, io.EOF is returned.
+func (d *DatasetReader) Read() (*DatasetEntry, error) {
+	var res DatasetEntry
+	if err := d.dec.Decode(&res); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, essentials.AddCtx("read dataset", err)
+	}
+	if res.Maze == nil {
+		return nil, errors.New("read dataset: missing maze")
+	}
+	return &res, nil
+}
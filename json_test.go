@@ -0,0 +1,80 @@
+package mazenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestMazeJSON(t *testing.T) {
+	expected := testingMaze()
+	data, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedData := `{"rows":4,"cols":4,"start":[2,1],"end":[1,3],` +
+		`"walls":[".ww.","w.w.","..ww","w..."]}`
+	if string(data) != expectedData {
+		t.Errorf("expected %s but got %s", expectedData, data)
+	}
+	var actual Maze
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&actual, expected) {
+		t.Errorf("expected %#v but got %#v", expected, &actual)
+	}
+
+	shouldFail := []string{
+		`{"rows":2,"cols":2,"walls":["..","."]}`,
+		`{"rows":2,"cols":2,"walls":[".."]}`,
+		`{"rows":1,"cols":2,"walls":[".?"]}`,
+	}
+	for _, s := range shouldFail {
+		if err := json.Unmarshal([]byte(s), &actual); err == nil {
+			t.Errorf("expected failure for %s", s)
+		}
+	}
+}
+
+func TestDataset(t *testing.T) {
+	var entries []*DatasetEntry
+	for i := int64(0); i < 3; i++ {
+		maze, err := GenerateSeed(&PrimGenerator{}, i, 11, 11)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params := map[string]string{"rows": "11", "cols": "11"}
+		entries = append(entries, NewDatasetEntry(maze, "prim", params, i))
+	}
+	if length := len(Solve(entries[0].Maze)) - 1; entries[0].OptimalLength != length {
+		t.Errorf("expected length %d but got %d", length, entries[0].OptimalLength)
+	}
+
+	var buf bytes.Buffer
+	w := NewDatasetWriter(&buf)
+	for _, e := range entries {
+		if err := w.Write(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := bytes.Count(buf.Bytes(), []byte("\n")); n != len(entries) {
+		t.Errorf("expected %d lines but got %d", len(entries), n)
+	}
+
+	r := NewDatasetReader(&buf)
+	for _, expected := range entries {
+		actual, err := r.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %#v but got %#v", expected, actual)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("expected EOF but got %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package mazenv
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+)
+
+// LayeredFloorSeparator is the line which separates floors
+// in the text representation of a LayeredMaze.
+const LayeredFloorSeparator = "---"
+
+// LayeredPosition represents a place in a LayeredMaze.
+type LayeredPosition struct {
+	Level int
+	Position
+}
+
+// LayeredMaze defines a maze with multiple floors, stacked
+// on top of each other and connected by stairs.
+type LayeredMaze struct {
+	// Floors stores the walls of every floor, from the
+	// bottom to the top.
+	// All floors must have the same dimensions.
+	//
+	// The Start and End of each floor are ignored.
+	Floors []*Maze
+
+	Start LayeredPosition
+	End   LayeredPosition
+
+	// Stairs contains, for every floor except the top one,
+	// a row-major list specifying which cells have stairs
+	// leading up to the floor above.
+	//
+	// There should be no wall on either end of the stairs.
+	Stairs [][]bool
+}
+
+// NewLayeredMaze creates a maze filled with walls.
+func NewLayeredMaze(levels, rows, cols int) *LayeredMaze {
+	res := &LayeredMaze{}
+	for i := 0; i < levels; i++ {
+		floor := &Maze{Rows: rows, Cols: cols, Walls: make([]bool, rows*cols)}
+		for j := range floor.Walls {
+			floor.Walls[j] = true
+		}
+		res.Floors = append(res.Floors, floor)
+		if i+1 < levels {
+			res.Stairs = append(res.Stairs, make([]bool, rows*cols))
+		}
+	}
+	return res
+}
+
+// ParseLayeredMaze parses a maze from a string.
+// See String for details on the format.
+func ParseLayeredMaze(s string) (maze *LayeredMaze, err error) {
+	defer essentials.AddCtxTo("parse layered maze", &err)
+	var floorLines [][]string
+	var curLines []string
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if strings.TrimSpace(line) == LayeredFloorSeparator {
+			floorLines = append(floorLines, curLines)
+			curLines = nil
+		} else {
+			curLines = append(curLines, line)
+		}
+	}
+	floorLines = append(floorLines, curLines)
+	for _, lines := range floorLines {
+		if len(lines) == 0 {
+			return nil, errors.New("empty floor")
+		}
+	}
+
+	rows := len(floorLines[0])
+	cols := len([]rune(floorLines[0][0]))
+	maze = NewLayeredMaze(len(floorLines), rows, cols)
+	var seenStart, seenEnd bool
+	for level, lines := range floorLines {
+		if len(lines) != rows {
+			return nil, errors.New("inconsistent number of rows")
+		}
+		floor := maze.Floors[level]
+		for row, line := range lines {
+			if len([]rune(line)) != cols {
+				return nil, errors.New("inconsistent number of columns")
+			}
+			for col, ch := range []rune(line) {
+				pos := LayeredPosition{Level: level, Position: Position{row, col}}
+				if ch != 'w' {
+					floor.Walls[floor.CellIndex(pos.Position)] = false
+				}
+				switch ch {
+				case '.', 'w':
+				case 'A':
+					if seenStart {
+						return nil, errors.New("multiple starts")
+					}
+					maze.Start = pos
+					seenStart = true
+				case 'x':
+					if seenEnd {
+						return nil, errors.New("multiple ends")
+					}
+					maze.End = pos
+					seenEnd = true
+				case 'u', 'b':
+					if level+1 == len(floorLines) {
+						return nil, errors.New("stairs lead above top floor")
+					}
+					maze.Stairs[level][floor.CellIndex(pos.Position)] = true
+				case 'd':
+				default:
+					return nil, errors.New("unknown cell: " + string(ch))
+				}
+			}
+		}
+	}
+	if !seenStart {
+		return nil, errors.New("missing start")
+	}
+	if !seenEnd {
+		return nil, errors.New("missing end")
+	}
+
+	// Make sure every staircase has a top and a bottom.
+	for level, lines := range floorLines {
+		for row, line := range lines {
+			for col, ch := range []rune(line) {
+				pos := LayeredPosition{Level: level, Position: Position{row, col}}
+				hasDown := ch == 'd' || ch == 'b'
+				if hasDown != maze.StairsDown(pos) {
+					return nil, errors.New("unmatched stairs")
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// Levels returns the number of floors.
+func (l *LayeredMaze) Levels() int {
+	return len(l.Floors)
+}
+
+// InBounds checks if the position is within the grid.
+func (l *LayeredMaze) InBounds(pos LayeredPosition) bool {
+	return pos.Level >= 0 && pos.Level < len(l.Floors) &&
+		l.Floors[pos.Level].InBounds(pos.Position)
+}
+
+// Positions returns all valid positions in the grid,
+// floor by floor.
+func (l *LayeredMaze) Positions() []LayeredPosition {
+	var res []LayeredPosition
+	for level, floor := range l.Floors {
+		for _, p := range floor.Positions() {
+			res = append(res, LayeredPosition{Level: level, Position: p})
+		}
+	}
+	return res
+}
+
+// Wall checks if the grid entry is a wall.
+//
+// If the cell is out of bounds, true is returned.
+func (l *LayeredMaze) Wall(pos LayeredPosition) bool {
+	if pos.Level < 0 || pos.Level >= len(l.Floors) {
+		return true
+	}
+	return l.Floors[pos.Level].Wall(pos.Position)
+}
+
+// StairsUp checks if there are stairs leading up from the
+// position.
+func (l *LayeredMaze) StairsUp(pos LayeredPosition) bool {
+	if !l.InBounds(pos) || pos.Level+1 >= len(l.Floors) {
+		return false
+	}
+	return l.Stairs[pos.Level][l.Floors[pos.Level].CellIndex(pos.Position)]
+}
+
+// StairsDown checks if there are stairs leading down from
+// the position.
+func (l *LayeredMaze) StairsDown(pos LayeredPosition) bool {
+	below := pos
+	below.Level--
+	return l.StairsUp(below)
+}
+
+// String produces an ASCII representation of the grid.
+//
+// Each floor is represented like Maze.String, except that
+// stairs are represented as 'u' (up), 'd' (down), or 'b'
+// (both).
+// Floors are listed from bottom to top, separated by a
+// line containing LayeredFloorSeparator.
+//
+// The start and end should not be on stairs, since they
+// take precedence in the representation.
+func (l *LayeredMaze) String() string {
+	var floors []string
+	for level, floor := range l.Floors {
+		rows := make([]string, floor.Rows)
+		for row := 0; row < floor.Rows; row++ {
+			for col := 0; col < floor.Cols; col++ {
+				pos := LayeredPosition{Level: level, Position: Position{row, col}}
+				ch := '.'
+				up, down := l.StairsUp(pos), l.StairsDown(pos)
+				if pos == l.Start {
+					ch = 'A'
+				} else if pos == l.End {
+					ch = 'x'
+				} else if l.Wall(pos) {
+					ch = 'w'
+				} else if up && down {
+					ch = 'b'
+				} else if up {
+					ch = 'u'
+				} else if down {
+					ch = 'd'
+				}
+				rows[row] += string(ch)
+			}
+		}
+		floors = append(floors, strings.Join(rows, "\n"))
+	}
+	return strings.Join(floors, "\n"+LayeredFloorSeparator+"\n")
+}
+
+// openNeighbors returns the positions reachable in a
+// single step from a position.
+func (l *LayeredMaze) openNeighbors(pos LayeredPosition) []LayeredPosition {
+	var res []LayeredPosition
+	for _, p := range neighboringSpaces(l.Floors[pos.Level], pos.Position) {
+		res = append(res, LayeredPosition{Level: pos.Level, Position: p})
+	}
+	if l.StairsUp(pos) {
+		res = append(res, LayeredPosition{Level: pos.Level + 1, Position: pos.Position})
+	}
+	if l.StairsDown(pos) {
+		res = append(res, LayeredPosition{Level: pos.Level - 1, Position: pos.Position})
+	}
+	return res
+}
+
+// SolveLayered finds an optimal solution to the maze.
+//
+// The solution is represented as a list of positions that
+// comprise the solution, including the start and end.
+// Taking a staircase counts as a single step.
+//
+// If no solution is found, nil is returned.
+func SolveLayered(l *LayeredMaze) []LayeredPosition {
+	parents := map[LayeredPosition]LayeredPosition{l.Start: l.Start}
+	queue := []LayeredPosition{l.Start}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		if pos == l.End {
+			var res []LayeredPosition
+			for pos != l.Start {
+				res = append(res, pos)
+				pos = parents[pos]
+			}
+			res = append(res, l.Start)
+			for i := 0; i < len(res)/2; i++ {
+				res[i], res[len(res)-1-i] = res[len(res)-1-i], res[i]
+			}
+			return res
+		}
+		for _, neighbor := range l.openNeighbors(pos) {
+			if _, ok := parents[neighbor]; !ok {
+				parents[neighbor] = pos
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return nil
+}
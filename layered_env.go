@@ -0,0 +1,132 @@
+package mazenv
+
+import (
+	"errors"
+
+	"github.com/unixpickle/anyrl"
+)
+
+// Additional indices in one-hot action vectors for
+// layered mazes.
+const (
+	ActionAscend = ActionLeft + 1 + iota
+	ActionDescend
+)
+
+// LayeredEnv is a generic multi-level maze environment.
+//
+// Actions are one-hot vectors with seven possibilities:
+// the five actions of Env, plus ActionAscend and
+// ActionDescend for using stairs.
+type LayeredEnv interface {
+	anyrl.Env
+
+	// Maze returns the environment's map.
+	Maze() *LayeredMaze
+
+	// Position returns the player's current position.
+	Position() LayeredPosition
+}
+
+// rawLayeredEnv is a barebones environment for a layered
+// maze.
+type rawLayeredEnv struct {
+	maze     *LayeredMaze
+	position LayeredPosition
+}
+
+// NewLayeredEnv creates a LayeredEnv for the maze.
+//
+// Observations are row-major representations of the
+// player's current floor, followed by a one-hot vector
+// indicating the current floor.
+// Each cell is represented as a boolean (is current
+// position), followed by a one-hot vector of four
+// components (space, wall, start, end), followed by two
+// booleans (has stairs up, has stairs down).
+//
+// Rewards are -1 until the maze is solved, at which point
+// the episode ends and the reward is 0.
+func NewLayeredEnv(maze *LayeredMaze) LayeredEnv {
+	return &rawLayeredEnv{maze: maze}
+}
+
+// Maze returns the maze.
+func (r *rawLayeredEnv) Maze() *LayeredMaze {
+	return r.maze
+}
+
+// Position returns the current position.
+func (r *rawLayeredEnv) Position() LayeredPosition {
+	return r.position
+}
+
+// Reset resets the player's position to the start.
+func (r *rawLayeredEnv) Reset() (obs []float64, err error) {
+	r.position = r.maze.Start
+	return r.observation(), nil
+}
+
+// Step takes a step in the environment.
+func (r *rawLayeredEnv) Step(action []float64) (obs []float64, reward float64,
+	done bool, err error) {
+	if r.position == r.maze.End {
+		err = errors.New("step: maze is already solved")
+		return
+	}
+	newPos := r.position
+	var actionIdx int
+	for i, x := range action {
+		if x != 0 {
+			actionIdx = i
+		}
+	}
+	switch actionIdx {
+	case ActionUp:
+		newPos.Row--
+	case ActionRight:
+		newPos.Col++
+	case ActionDown:
+		newPos.Row++
+	case ActionLeft:
+		newPos.Col--
+	case ActionAscend:
+		if r.maze.StairsUp(r.position) {
+			newPos.Level++
+		}
+	case ActionDescend:
+		if r.maze.StairsDown(r.position) {
+			newPos.Level--
+		}
+	}
+	if !r.maze.Wall(newPos) {
+		r.position = newPos
+	}
+	if r.position == r.maze.End {
+		reward = 0
+		done = true
+	} else {
+		reward = -1
+	}
+	obs = r.observation()
+	return
+}
+
+func (r *rawLayeredEnv) observation() []float64 {
+	var res []float64
+	for _, p := range r.maze.Floors[r.position.Level].Positions() {
+		pos := LayeredPosition{Level: r.position.Level, Position: p}
+		cellType := CellEmpty
+		if pos == r.maze.Start {
+			cellType = CellStart
+		} else if pos == r.maze.End {
+			cellType = CellEnd
+		} else if r.maze.Wall(pos) {
+			cellType = CellWall
+		}
+		res = append(res, boolToFloat(pos == r.position))
+		res = append(res, oneHot(4, cellType)...)
+		res = append(res, boolToFloat(r.maze.StairsUp(pos)), boolToFloat(r.maze.StairsDown(pos)))
+	}
+	return append(res, oneHot(r.maze.Levels(), r.position.Level)...)
+}
@@ -0,0 +1,59 @@
+package mazenv
+
+import "testing"
+
+func TestLayeredEnv(t *testing.T) {
+	maze, err := ParseLayeredMaze("A.u\nww.\n---\nx.d\n...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := NewLayeredEnv(maze)
+
+	obs, err := env.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedInitial := []float64{
+		1, 0, 0, 1, 0, 0, 0,
+		0, 1, 0, 0, 0, 0, 0,
+		0, 1, 0, 0, 0, 1, 0,
+
+		0, 0, 1, 0, 0, 0, 0,
+		0, 0, 1, 0, 0, 0, 0,
+		0, 1, 0, 0, 0, 0, 0,
+
+		1, 0,
+	}
+	testObsEqual(t, obs, expectedInitial)
+
+	for _, act := range []int{ActionNop, ActionUp, ActionAscend, ActionDescend} {
+		obs, reward, done, err := env.Step(oneHotLayeredAction(act))
+		testNotDoneStepResult(t, reward, done, err)
+		testObsEqual(t, obs, expectedInitial)
+	}
+
+	for _, act := range []int{ActionRight, ActionRight, ActionAscend, ActionLeft} {
+		_, reward, done, err := env.Step(oneHotLayeredAction(act))
+		testNotDoneStepResult(t, reward, done, err)
+	}
+	if env.Position() != (LayeredPosition{1, Position{0, 1}}) {
+		t.Fatalf("unexpected position: %v", env.Position())
+	}
+
+	obs, reward, done, err := env.Step(oneHotLayeredAction(ActionLeft))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || reward != 0 {
+		t.Error("expected episode to end with reward 0")
+	}
+	if obs[len(obs)-1] != 1 || obs[0] != 1 || obs[1] != 0 {
+		t.Errorf("unexpected observation: %v", obs)
+	}
+}
+
+func oneHotLayeredAction(idx int) []float64 {
+	data := make([]float64, 7)
+	data[idx] = 1
+	return data
+}
@@ -0,0 +1,142 @@
+package mazenv
+
+import (
+	"errors"
+	"flag"
+	"math/rand"
+
+	"github.com/unixpickle/essentials"
+)
+
+// A LayeredGenerator generates multi-level mazes.
+type LayeredGenerator interface {
+	Generate(rows, cols int) (*LayeredMaze, error)
+	GenerateRand(rng *rand.Rand, rows, cols int) (*LayeredMaze, error)
+}
+
+// LayeredPrimGenerator is a LayeredGenerator that extends
+// PrimGenerator's algorithm across several floors.
+//
+// Like PrimGenerator, it produces mazes where there is
+// exactly one path between any two spaces.
+type LayeredPrimGenerator struct {
+	// Levels is the number of floors.
+	//
+	// If 0, a default of 2 is used.
+	Levels int
+
+	// StairProbability is the probability, at each step of
+	// the algorithm, of extending the maze to another floor
+	// rather than along the current one.
+	// The value may range from 0 to 1.
+	//
+	// If 0, a default of 0.05 is used.
+	StairProbability float64
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (l *LayeredPrimGenerator) Description() string {
+	return "randomized variant of Prim's algorithm (multiple floors)"
+}
+
+// AddFlags adds the generator's options as flags.
+func (l *LayeredPrimGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.IntVar(&l.Levels, "levels", 2, "number of floors")
+	fs.Float64Var(&l.StairProbability, "stairs", 0.05, "chance of adding stairs at each step")
+}
+
+// Generate generates a random maze.
+func (l *LayeredPrimGenerator) Generate(rows, cols int) (*LayeredMaze, error) {
+	return l.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (l *LayeredPrimGenerator) GenerateRand(rng *rand.Rand, rows,
+	cols int) (*LayeredMaze, error) {
+	rng = randOrGlobal(rng)
+	levels := l.Levels
+	if levels == 0 {
+		levels = 2
+	}
+	stairProb := l.StairProbability
+	if stairProb == 0 {
+		stairProb = 0.05
+	}
+
+	maze := NewLayeredMaze(levels, rows, cols)
+
+	type edge struct {
+		From LayeredPosition
+		To   LayeredPosition
+	}
+	var planar, vertical []edge
+	open := func(from, to LayeredPosition) {
+		floor := maze.Floors[to.Level]
+		floor.Walls[floor.CellIndex(to.Position)] = false
+		if from.Level < to.Level {
+			maze.Stairs[from.Level][floor.CellIndex(from.Position)] = true
+		} else if from.Level > to.Level {
+			maze.Stairs[to.Level][floor.CellIndex(to.Position)] = true
+		}
+		for _, p := range neighbors(floor, to.Position) {
+			planar = append(planar, edge{From: to, To: LayeredPosition{Level: to.Level, Position: p}})
+		}
+		for _, level := range []int{to.Level - 1, to.Level + 1} {
+			if level >= 0 && level < levels {
+				vertical = append(vertical, edge{
+					From: to,
+					To:   LayeredPosition{Level: level, Position: to.Position},
+				})
+			}
+		}
+	}
+	first := LayeredPosition{
+		Level: rng.Intn(levels),
+		Position: Position{
+			Row: rng.Intn(rows),
+			Col: rng.Intn(cols),
+		},
+	}
+	open(first, first)
+
+	for len(planar) > 0 || len(vertical) > 0 {
+		edges := &planar
+		if len(vertical) > 0 && (len(planar) == 0 || rng.Float64() < stairProb) {
+			edges = &vertical
+		}
+		idx := rng.Intn(len(*edges))
+		e := (*edges)[idx]
+		essentials.UnorderedDelete(edges, idx)
+		if !maze.Wall(e.To) {
+			continue
+		}
+
+		// Only open the cell if it would be connected to
+		// exactly one space, avoiding loops.
+		connections := len(neighboringSpaces(maze.Floors[e.To.Level], e.To.Position))
+		if e.From.Level != e.To.Level {
+			connections++
+		}
+		if connections == 1 {
+			open(e.From, e.To)
+		}
+	}
+
+	var spaces []LayeredPosition
+	for _, p := range maze.Positions() {
+		if !maze.Wall(p) && !maze.StairsUp(p) && !maze.StairsDown(p) {
+			spaces = append(spaces, p)
+		}
+	}
+	if len(spaces) < 2 {
+		return nil, errors.New("not enough spaces")
+	}
+	perm := rng.Perm(len(spaces))
+	maze.Start, maze.End = spaces[perm[0]], spaces[perm[1]]
+
+	return maze, nil
+}
@@ -0,0 +1,48 @@
+package mazenv
+
+import "testing"
+
+func TestLayeredPrimGenerator(t *testing.T) {
+	gen := &LayeredPrimGenerator{Levels: 3, StairProbability: 0.1}
+	for i := 0; i < 5; i++ {
+		m, err := gen.Generate(9, 11)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Levels() != 3 || len(m.Stairs) != 2 {
+			t.Fatal("invalid number of levels")
+		}
+		if m.Start == m.End || m.Wall(m.Start) || m.Wall(m.End) {
+			t.Error("invalid start or end")
+		}
+
+		var numSpaces, numEdges int
+		for level, floor := range m.Floors {
+			var floorSpaces int
+			for _, p := range floor.Positions() {
+				pos := LayeredPosition{Level: level, Position: p}
+				if !m.Wall(pos) {
+					floorSpaces++
+					numEdges += len(m.openNeighbors(pos))
+				}
+			}
+			if floorSpaces == 0 {
+				t.Errorf("floor %d is empty", level)
+			}
+			numSpaces += floorSpaces
+		}
+		if numEdges/2 != numSpaces-1 {
+			t.Errorf("maze has loops or is disconnected: %s", m)
+		}
+		if SolveLayered(m) == nil {
+			t.Errorf("unsolvable: %s", m)
+		}
+
+		parsed, err := ParseLayeredMaze(m.String())
+		if err != nil {
+			t.Fatal(err)
+		} else if parsed.String() != m.String() {
+			t.Error("maze did not survive round trip")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package mazenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLayeredMazeString(t *testing.T) {
+	expected := "A.u\nww.\n---\nx.d\n..."
+	maze, err := ParseLayeredMaze(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maze.Levels() != 2 || maze.Floors[0].Rows != 2 || maze.Floors[0].Cols != 3 {
+		t.Fatal("invalid dimensions")
+	}
+	if !maze.StairsUp(LayeredPosition{0, Position{0, 2}}) ||
+		!maze.StairsDown(LayeredPosition{1, Position{0, 2}}) {
+		t.Error("missing stairs")
+	}
+	if maze.End != (LayeredPosition{1, Position{0, 0}}) {
+		t.Errorf("unexpected end: %v", maze.End)
+	}
+	if actual := maze.String(); actual != expected {
+		t.Errorf("expected %#v but got %#v", expected, actual)
+	}
+
+	shouldFail := []string{
+		"A.u\nww.\n---\nx..\n...",
+		"A..\nww.\n---\nx.d\n...",
+		"A.u\nww.\n---\nx.u\n...",
+		"A.d\nww.\n---\nx..\n...",
+		"A..\nww.\n---\nx..",
+		"A..\nww.\n---\nx..\n..",
+		"A..\nww.\n---\n...\n...",
+		"A..\nww?\n---\nx..\n...",
+		"---\nA.x",
+		"A.x\n---",
+		"A..\n---\n---\n..x",
+		"",
+	}
+	for _, s := range shouldFail {
+		if _, err := ParseLayeredMaze(s); err == nil {
+			t.Errorf("expected failure for %#v", s)
+		}
+	}
+}
+
+func TestSolveLayered(t *testing.T) {
+	maze, err := ParseLayeredMaze("A.u\nww.\n---\nx.d\n...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := SolveLayered(maze)
+	expected := []LayeredPosition{
+		{0, Position{0, 0}},
+		{0, Position{0, 1}},
+		{0, Position{0, 2}},
+		{1, Position{0, 2}},
+		{1, Position{0, 1}},
+		{1, Position{0, 0}},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+
+	unsolvable, err := ParseLayeredMaze("A.u\nwww\n---\nw.d\nxww")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if SolveLayered(unsolvable) != nil {
+		t.Error("found false solution")
+	}
+}
@@ -0,0 +1,120 @@
+package mazenv
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+// LengthGenerator wraps a Generator and moves the start
+// and end of each maze so that the shortest solution has
+// a length in a given range.
+//
+// Lengths are measured in steps, which is one less than
+// the number of positions returned by Solve.
+type LengthGenerator struct {
+	Generator Generator
+
+	// MinLength is the minimum solution length.
+	MinLength int
+
+	// MaxLength is the maximum solution length.
+	//
+	// If 0, there is no maximum.
+	MaxLength int
+
+	// Farthest, if true, places the end at the farthest
+	// cell from the start which satisfies the length
+	// constraint.
+	Farthest bool
+
+	// Percentile, if non-zero, places the end at the given
+	// percentile (from 0 to 1) of the distances from the
+	// start to every cell satisfying the constraint.
+	//
+	// This is ignored if Farthest is set.
+	Percentile float64
+
+	// MaxTries is the number of mazes to try before giving
+	// up on satisfying the constraint.
+	//
+	// If 0, a default of 10 is used.
+	MaxTries int
+}
+
+// Generate generates a maze from the wrapped Generator
+// and places its start and end.
+func (l *LengthGenerator) Generate(rows, cols int) (*Maze, error) {
+	return l.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+// The wrapped Generator only uses rng if it implements
+// RandGenerator.
+//
+// If rng is nil, the global source from math/rand is used.
+func (l *LengthGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	if l.Percentile < 0 || l.Percentile > 1 {
+		return nil, errors.New("generate: percentile must be between 0 and 1")
+	} else if l.MaxLength > 0 && l.MaxLength < l.MinLength {
+		return nil, errors.New("generate: maximum length is less than minimum length")
+	}
+	maxTries := l.MaxTries
+	if maxTries == 0 {
+		maxTries = 10
+	}
+	for i := 0; i < maxTries; i++ {
+		maze, err := generateRand(l.Generator, rng, rows, cols)
+		if err != nil {
+			return nil, err
+		}
+		if l.placeStartEnd(rng, maze) {
+			return maze, nil
+		}
+	}
+	return nil, errors.New("generate: could not satisfy solution length constraint")
+}
+
+// placeStartEnd attempts to place the start and end of a
+// maze to satisfy the constraint.
+// It returns false if no placement was found.
+func (l *LengthGenerator) placeStartEnd(rng *rand.Rand, m *Maze) bool {
+	const maxStarts = 16
+
+	starts := shuffledSpaces(rng, m)
+	if len(starts) > maxStarts {
+		starts = starts[:maxStarts]
+	}
+	for _, start := range starts {
+		dists := distances(m, start)
+		var ends []Position
+		for _, p := range m.Positions() {
+			dist := dists[m.CellIndex(p)]
+			if dist > 0 && dist >= l.MinLength && (l.MaxLength == 0 || dist <= l.MaxLength) {
+				ends = append(ends, p)
+			}
+		}
+		if len(ends) == 0 {
+			continue
+		}
+		rng.Shuffle(len(ends), func(i, j int) {
+			ends[i], ends[j] = ends[j], ends[i]
+		})
+		end := ends[0]
+		if l.Farthest || l.Percentile != 0 {
+			sort.SliceStable(ends, func(i, j int) bool {
+				return dists[m.CellIndex(ends[i])] < dists[m.CellIndex(ends[j])]
+			})
+			if l.Farthest {
+				end = ends[len(ends)-1]
+			} else {
+				end = ends[int(l.Percentile*float64(len(ends)-1)+0.5)]
+			}
+		}
+		m.Start, m.End = start, end
+		return true
+	}
+	return false
+}
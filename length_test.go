@@ -0,0 +1,88 @@
+package mazenv
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestLengthGenerator(t *testing.T) {
+	gen := &LengthGenerator{
+		Generator: &PrimGenerator{},
+		MinLength: 20,
+		MaxLength: 25,
+	}
+	for i := 0; i < 10; i++ {
+		m, err := gen.Generate(21, 15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if length := len(Solve(m)) - 1; length < 20 || length > 25 {
+			t.Errorf("unexpected solution length: %d", length)
+		}
+	}
+}
+
+func TestLengthGeneratorFarthest(t *testing.T) {
+	gen := &LengthGenerator{Generator: &BacktrackerGenerator{}, Farthest: true}
+	for i := 0; i < 10; i++ {
+		m, err := gen.Generate(21, 15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var maxDist int
+		for _, d := range distances(m, m.Start) {
+			if d > maxDist {
+				maxDist = d
+			}
+		}
+		if length := len(Solve(m)) - 1; length != maxDist {
+			t.Errorf("expected length %d but got %d", maxDist, length)
+		}
+	}
+}
+
+func TestLengthGeneratorImpossible(t *testing.T) {
+	gen := &LengthGenerator{Generator: &PrimGenerator{}, MinLength: 1000}
+	if _, err := gen.Generate(11, 11); err == nil {
+		t.Error("expected an error")
+	}
+
+	// This should fail without generating any mazes.
+	gen = &LengthGenerator{Generator: &PrimGenerator{}, MinLength: 10, MaxLength: 5}
+	if _, err := gen.Generate(11, 11); err == nil {
+		t.Error("expected an error for an empty length range")
+	}
+}
+
+func TestLengthGeneratorPercentile(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	for _, percentile := range []float64{0.25, 0.5, 1} {
+		gen := &LengthGenerator{Generator: &PrimGenerator{}, Percentile: percentile}
+		for i := 0; i < 5; i++ {
+			m, err := gen.GenerateRand(rng, 21, 15)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reachable []int
+			for _, d := range distances(m, m.Start) {
+				if d > 0 {
+					reachable = append(reachable, d)
+				}
+			}
+			sort.Ints(reachable)
+			expected := reachable[int(percentile*float64(len(reachable)-1)+0.5)]
+			if length := len(Solve(m)) - 1; length != expected {
+				t.Errorf("percentile %f: expected length %d but got %d", percentile, expected,
+					length)
+			}
+		}
+	}
+
+	for _, percentile := range []float64{-0.5, 1.5} {
+		gen := &LengthGenerator{Generator: &PrimGenerator{}, Percentile: percentile}
+		if _, err := gen.Generate(11, 11); err == nil {
+			t.Errorf("expected an error for percentile %f", percentile)
+		}
+	}
+}
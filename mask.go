@@ -0,0 +1,189 @@
+package mazenv
+
+import (
+	"errors"
+	"image"
+	"io"
+	"math/rand"
+	"strings"
+
+	// Support PNG and JPEG masks.
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/unixpickle/essentials"
+)
+
+// Mask specifies which cells of a grid may be used by a
+// maze.
+type Mask struct {
+	Rows int
+	Cols int
+
+	// Cells is a row-major list specifying which cells are
+	// usable.
+	Cells []bool
+}
+
+// ParseMask parses a mask from a string in the format of
+// Maze.String.
+//
+// Walls ('w') are unusable, while spaces ('.') are usable.
+// For convenience, starts ('A') and ends ('x') are also
+// usable, so any maze can be used as a mask.
+func ParseMask(s string) (mask *Mask, err error) {
+	defer essentials.AddCtxTo("parse mask", &err)
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	mask = &Mask{Rows: len(lines), Cols: len([]rune(lines[0]))}
+	for _, line := range lines {
+		if len([]rune(line)) != mask.Cols {
+			return nil, errors.New("inconsistent number of columns")
+		}
+		for _, ch := range line {
+			switch ch {
+			case 'w':
+				mask.Cells = append(mask.Cells, false)
+			case '.', 'A', 'x':
+				mask.Cells = append(mask.Cells, true)
+			default:
+				return nil, errors.New("unknown cell: " + string(ch))
+			}
+		}
+	}
+	return
+}
+
+// MaskFromImage creates a mask from an image.
+//
+// Light, opaque pixels are usable, while dark or
+// transparent pixels are not.
+//
+// The image is scaled to the given number of rows and
+// columns, or kept at its original size if they are 0.
+func MaskFromImage(img image.Image, rows, cols int) *Mask {
+	bounds := img.Bounds()
+	if rows == 0 || cols == 0 {
+		rows, cols = bounds.Dy(), bounds.Dx()
+	}
+	mask := &Mask{Rows: rows, Cols: cols, Cells: make([]bool, rows*cols)}
+	for row := 0; row < rows; row++ {
+		y := bounds.Min.Y + (2*row+1)*bounds.Dy()/(2*rows)
+		for col := 0; col < cols; col++ {
+			x := bounds.Min.X + (2*col+1)*bounds.Dx()/(2*cols)
+			r, g, b, a := img.At(x, y).RGBA()
+			// Compare un-premultiplied luminance to half.
+			lum := (299*r + 587*g + 114*b) / 1000
+			mask.Cells[row*cols+col] = a >= 0x8000 && lum*2 >= a
+		}
+	}
+	return mask
+}
+
+// ReadMaskImage decodes a PNG or JPEG image and creates a
+// mask from it with MaskFromImage.
+func ReadMaskImage(r io.Reader, rows, cols int) (mask *Mask, err error) {
+	defer essentials.AddCtxTo("read mask image", &err)
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return MaskFromImage(img, rows, cols), nil
+}
+
+// Usable checks if a cell may be used.
+//
+// Cells out of bounds are never usable.
+func (m *Mask) Usable(pos Position) bool {
+	return pos.Row >= 0 && pos.Row < m.Rows && pos.Col >= 0 && pos.Col < m.Cols &&
+		m.Cells[pos.Row*m.Cols+pos.Col]
+}
+
+// MaskedGenerator wraps a Generator to produce mazes that
+// only use the cells allowed by a mask.
+//
+// Cells outside of the mask are always walls.
+// If the mask itself is disconnected, only its largest
+// connected part is used.
+// If cutting out the mask disconnects the maze, walls
+// inside the mask are opened to reconnect it.
+// The start and end are then placed randomly.
+type MaskedGenerator struct {
+	Generator Generator
+	Mask      *Mask
+}
+
+// Generate generates a masked maze.
+//
+// The dimensions must match the mask.
+func (m *MaskedGenerator) Generate(rows, cols int) (*Maze, error) {
+	return m.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+// The wrapped Generator only uses rng if it implements
+// RandGenerator.
+//
+// If rng is nil, the global source from math/rand is used.
+func (m *MaskedGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	if rows != m.Mask.Rows || cols != m.Mask.Cols {
+		return nil, errors.New("generate: dimensions do not match mask")
+	}
+	region := m.Mask.largestRegion()
+	inRegion := func(p Position) bool {
+		return p.Row >= 0 && p.Row < rows && p.Col >= 0 && p.Col < cols &&
+			region[p.Row*cols+p.Col]
+	}
+
+	// Some generators, such as caves, may leave the mask
+	// with hardly any spaces, in which case we try again.
+	for i := 0; i < maskedAttempts; i++ {
+		maze, err := generateRand(m.Generator, rng, rows, cols)
+		if err != nil {
+			return nil, err
+		}
+		var numSpaces int
+		for i, usable := range region {
+			if !usable {
+				maze.Walls[i] = true
+			} else if !maze.Walls[i] {
+				numSpaces++
+			}
+		}
+		if numSpaces < 2 {
+			continue
+		}
+		connectRegions(maze, inRegion)
+		if err := placeStartEnd(rng, maze); err != nil {
+			return nil, err
+		}
+		return maze, nil
+	}
+	return nil, errors.New("generate: not enough spaces inside mask")
+}
+
+// maskedAttempts is the number of mazes MaskedGenerator
+// generates before giving up on finding spaces inside the
+// mask.
+const maskedAttempts = 10
+
+// largestRegion finds the largest connected set of usable
+// cells, in the same order as m.Cells.
+func (m *Mask) largestRegion() []bool {
+	grid := &Maze{Rows: m.Rows, Cols: m.Cols, Walls: make([]bool, len(m.Cells))}
+	for i, usable := range m.Cells {
+		grid.Walls[i] = !usable
+	}
+	res := make([]bool, len(m.Cells))
+	var largest []Position
+	for _, region := range spaceRegions(grid) {
+		if len(region) > len(largest) {
+			largest = region
+		}
+	}
+	for _, p := range largest {
+		res[grid.CellIndex(p)] = true
+	}
+	return res
+}
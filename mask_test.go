@@ -0,0 +1,80 @@
+package mazenv
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParseMask(t *testing.T) {
+	mask, err := ParseMask("w.A\nx.w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Mask{
+		Rows:  2,
+		Cols:  3,
+		Cells: []bool{false, true, true, true, true, false},
+	}
+	if !reflect.DeepEqual(mask, expected) {
+		t.Errorf("expected %v but got %v", expected, mask)
+	}
+	for _, s := range []string{"w.\n.", "w?\n.."} {
+		if _, err := ParseMask(s); err == nil {
+			t.Errorf("expected failure for %#v", s)
+		}
+	}
+}
+
+func TestMaskFromImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x >= 20 {
+				img.Set(x, y, color.White)
+			} else if y >= 10 {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	mask := MaskFromImage(img, 2, 4)
+	expected := []bool{false, false, true, true, false, false, true, true}
+	if !reflect.DeepEqual(mask.Cells, expected) {
+		t.Errorf("expected %v but got %v", expected, mask.Cells)
+	}
+	if mask := MaskFromImage(img, 0, 0); mask.Rows != 20 || mask.Cols != 40 {
+		t.Error("unexpected dimensions")
+	}
+}
+
+func TestMaskedGenerator(t *testing.T) {
+	// Two disconnected diamonds, one larger than the other.
+	mask := &Mask{Rows: 21, Cols: 31}
+	for _, p := range (&Maze{Rows: 21, Cols: 31}).Positions() {
+		big := manhattanDistance(p, Position{10, 10}) <= 10
+		small := manhattanDistance(p, Position{10, 26}) <= 4
+		mask.Cells = append(mask.Cells, big || small)
+	}
+
+	for name, gen := range testGenerators() {
+		t.Run(name, func(t *testing.T) {
+			masked := &MaskedGenerator{Generator: gen, Mask: mask}
+			m, err := masked.Generate(21, 31)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range m.Positions() {
+				if !m.Wall(p) && manhattanDistance(p, Position{10, 10}) > 10 {
+					t.Fatalf("space outside of mask: %#v", m.String())
+				}
+			}
+			if n := len(spaceRegions(m)); n != 1 {
+				t.Errorf("expected 1 region but got %d", n)
+			}
+			if Solve(m) == nil {
+				t.Errorf("unsolvable: %#v", m.String())
+			}
+		})
+	}
+}
@@ -3,8 +3,15 @@ package mazenv
 import (
 	"errors"
 	"strings"
+)
 
-	"github.com/unixpickle/essentials"
+// These errors are returned when parsing or importing a
+// maze without exactly one start and one end.
+var (
+	ErrMissingStart   = errors.New("parse maze: missing start")
+	ErrMissingEnd     = errors.New("parse maze: missing end")
+	ErrMultipleStarts = errors.New("parse maze: multiple starts")
+	ErrMultipleEnds   = errors.New("parse maze: multiple ends")
 )
 
 // Position represents a place on a Maze.
@@ -17,6 +24,22 @@ func (p Position) addOne() Position {
 	return Position{Row: p.Row + 1, Col: p.Col + 1}
 }
 
+// A Grid is a matrix of walls and spaces with a start
+// and an end, regardless of how the walls are stored.
+//
+// Both *Maze and *PackedMaze implement Grid.
+type Grid interface {
+	// Size returns the dimensions of the grid.
+	Size() (rows, cols int)
+
+	// Endpoints returns the start and end positions.
+	Endpoints() (start, end Position)
+
+	// Wall checks if the grid entry is a wall.
+	// Out of bounds cells are walls.
+	Wall(pos Position) bool
+}
+
 // Maze defines a matrix of cells which comprise a maze.
 type Maze struct {
 	Rows int
@@ -35,12 +58,15 @@ type Maze struct {
 
 // ParseMaze parses a maze from a string.
 // See String for details on the format.
-func ParseMaze(s string) (maze *Maze, err error) {
-	defer essentials.AddCtxTo("parse maze", &err)
+// The block style produced by Text is also accepted.
+//
+// Unknown characters are treated as spaces.
+// For stricter parsing, see ParseMazeStrict.
+func ParseMaze(s string) (*Maze, error) {
 	lines := strings.Split(strings.TrimSpace(s), "\n")
-	maze = &Maze{}
+	maze := &Maze{}
 	if len(lines) == 0 {
-		return
+		return maze, nil
 	}
 	maze.Rows = len(lines)
 	maze.Cols = len([]rune(lines[0]))
@@ -48,36 +74,46 @@ func ParseMaze(s string) (maze *Maze, err error) {
 	var seenStart, seenEnd bool
 	for row, line := range lines {
 		if len([]rune(line)) != maze.Cols {
-			return nil, errors.New("inconsistent number of columns")
+			return nil, errors.New("parse maze: inconsistent number of columns")
 		}
-		for col, ch := range line {
+		for col, ch := range []rune(line) {
 			pos := Position{row, col}
 			switch ch {
-			case '.':
-			case 'w':
+			case '.', '░':
+			case 'w', '█':
 				maze.Walls[maze.CellIndex(pos)] = true
 			case 'A':
 				maze.Start = pos
 				if seenStart {
-					return nil, errors.New("multiple starts")
+					return nil, ErrMultipleStarts
 				}
 				seenStart = true
 			case 'x':
 				maze.End = pos
 				if seenEnd {
-					return nil, errors.New("multiple ends")
+					return nil, ErrMultipleEnds
 				}
 				seenEnd = true
 			}
 		}
 	}
 	if !seenStart {
-		return nil, errors.New("missing start")
+		return nil, ErrMissingStart
 	}
 	if !seenEnd {
-		return nil, errors.New("missing end")
+		return nil, ErrMissingEnd
 	}
-	return
+	return maze, nil
+}
+
+// Size returns the number of rows and columns.
+func (m *Maze) Size() (rows, cols int) {
+	return m.Rows, m.Cols
+}
+
+// Endpoints returns the start and end positions.
+func (m *Maze) Endpoints() (start, end Position) {
+	return m.Start, m.End
 }
 
 // InBounds checks if the position is within the grid.
@@ -148,6 +184,8 @@ func (m *Maze) Bordered() *Maze {
 // Every wall is represented as a 'w', every space as a
 // '.', the start as 'A', and the end as 'x'.
 // Each row is separated by a newline.
+//
+// For other styles, see Text.
 func (m *Maze) String() string {
 	rows := make([]string, m.Rows)
 	for row := 0; row < m.Rows; row++ {
@@ -0,0 +1,134 @@
+package mazenv
+
+// PackedMaze is like a Maze, but it stores its walls in a
+// Bitset, using one bit per cell rather than one byte.
+//
+// This saves memory and improves cache behavior for very
+// large grids.
+// A PackedMaze is a Grid, so any Solver can solve it,
+// and NewPackedEnv creates an environment for it.
+type PackedMaze struct {
+	Rows int
+	Cols int
+
+	Start Position
+	End   Position
+
+	// Walls is a row-major list specifying which cells in
+	// the maze are walls.
+	Walls Bitset
+}
+
+// NewPackedMaze creates a maze with no walls.
+func NewPackedMaze(rows, cols int) *PackedMaze {
+	return &PackedMaze{Rows: rows, Cols: cols, Walls: NewBitset(rows * cols)}
+}
+
+// Pack creates a PackedMaze with the same contents as m.
+func (m *Maze) Pack() *PackedMaze {
+	res := NewPackedMaze(m.Rows, m.Cols)
+	res.Start, res.End = m.Start, m.End
+	for i, wall := range m.Walls {
+		if wall {
+			res.Walls.Set(i, true)
+		}
+	}
+	return res
+}
+
+// Unpack creates a Maze with the same contents as p.
+func (p *PackedMaze) Unpack() *Maze {
+	res := &Maze{
+		Rows:  p.Rows,
+		Cols:  p.Cols,
+		Start: p.Start,
+		End:   p.End,
+		Walls: make([]bool, p.Rows*p.Cols),
+	}
+	for i := range res.Walls {
+		res.Walls[i] = p.Walls.Get(i)
+	}
+	return res
+}
+
+// Size returns the number of rows and columns.
+func (p *PackedMaze) Size() (rows, cols int) {
+	return p.Rows, p.Cols
+}
+
+// Endpoints returns the start and end positions.
+func (p *PackedMaze) Endpoints() (start, end Position) {
+	return p.Start, p.End
+}
+
+// InBounds checks if the position is within the grid.
+func (p *PackedMaze) InBounds(pos Position) bool {
+	return pos.Row >= 0 && pos.Row < p.Rows &&
+		pos.Col >= 0 && pos.Col < p.Cols
+}
+
+// Positions returns all valid positions in the grid in
+// the same order as p.Walls.
+func (p *PackedMaze) Positions() []Position {
+	res := make([]Position, 0, p.Rows*p.Cols)
+	for row := 0; row < p.Rows; row++ {
+		for col := 0; col < p.Cols; col++ {
+			res = append(res, Position{row, col})
+		}
+	}
+	return res
+}
+
+// Wall checks if the grid entry is a wall.
+//
+// If the cell is out of bounds, true is returned.
+func (p *PackedMaze) Wall(pos Position) bool {
+	if !p.InBounds(pos) {
+		return true
+	}
+	return p.Walls.Get(p.CellIndex(pos))
+}
+
+// SetWall sets whether or not a cell is a wall.
+//
+// The position must be within bounds.
+func (p *PackedMaze) SetWall(pos Position, wall bool) {
+	p.Walls.Set(p.CellIndex(pos), wall)
+}
+
+// CellIndex gets the index for the cell.
+//
+// The position must be within bounds.
+func (p *PackedMaze) CellIndex(pos Position) int {
+	if !p.InBounds(pos) {
+		panic("out of bounds")
+	}
+	return pos.Row*p.Cols + pos.Col
+}
+
+// NumWalls counts the walls in the grid.
+func (p *PackedMaze) NumWalls() int {
+	return p.Walls.Count()
+}
+
+// RowWalls counts the walls in a row.
+func (p *PackedMaze) RowWalls(row int) int {
+	return p.Walls.CountRange(row*p.Cols, (row+1)*p.Cols)
+}
+
+// ColWalls counts the walls in a column.
+func (p *PackedMaze) ColWalls(col int) int {
+	var res int
+	for i := col; i < p.Rows*p.Cols; i += p.Cols {
+		if p.Walls.Get(i) {
+			res++
+		}
+	}
+	return res
+}
+
+// Solve finds an optimal solution to the maze, like the
+// Solve function for a Maze.
+func (p *PackedMaze) Solve() []Position {
+	return (&BFSSolver{}).Solve(p)
+}
@@ -0,0 +1,36 @@
+package mazenv
+
+import "github.com/unixpickle/anyrl"
+
+// PackedEnv is a generic environment for packed mazes.
+//
+// It behaves exactly like Env, but the maze is stored in
+// a PackedMaze to save memory on very large grids.
+type PackedEnv interface {
+	anyrl.Env
+
+	// Maze returns the environment's map.
+	Maze() *PackedMaze
+
+	// Position returns the player's current position.
+	Position() Position
+}
+
+// rawPackedEnv is a barebones environment for a packed
+// maze.
+type rawPackedEnv struct {
+	gridEnv
+	maze *PackedMaze
+}
+
+// NewPackedEnv creates a PackedEnv for the maze.
+//
+// Observations and rewards are the same as for NewEnv.
+func NewPackedEnv(maze *PackedMaze) PackedEnv {
+	return &rawPackedEnv{gridEnv: gridEnv{grid: maze}, maze: maze}
+}
+
+// Maze returns the maze.
+func (r *rawPackedEnv) Maze() *PackedMaze {
+	return r.maze
+}
@@ -0,0 +1,44 @@
+package mazenv
+
+import "testing"
+
+func TestPackedEnv(t *testing.T) {
+	maze, err := ParseMaze("...w\n" + ".wxw\n" + "Awww\n" + "...w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, packedEnv := NewEnv(maze), NewPackedEnv(maze.Pack())
+
+	expected, err := env.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := packedEnv.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testObsEqual(t, actual, expected)
+
+	for _, act := range []int{ActionRight, ActionUp, ActionUp, ActionRight, ActionRight,
+		ActionDown} {
+		expected, expectedReward, expectedDone, err := env.Step(oneHotAction(act))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, reward, done, err := packedEnv.Step(oneHotAction(act))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testObsEqual(t, actual, expected)
+		if reward != expectedReward || done != expectedDone {
+			t.Errorf("expected reward %f and done %v but got %f and %v", expectedReward,
+				expectedDone, reward, done)
+		}
+		if packedEnv.Position() != env.Position() {
+			t.Errorf("expected position %v but got %v", env.Position(), packedEnv.Position())
+		}
+	}
+	if packedEnv.Position() != maze.End {
+		t.Error("expected to reach the end")
+	}
+}
@@ -0,0 +1,76 @@
+package mazenv
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPackedMaze(t *testing.T) {
+	maze := testingMaze()
+	packed := maze.Pack()
+	if !reflect.DeepEqual(packed.Unpack(), maze) {
+		t.Errorf("expected %#v but got %#v", maze.String(), packed.Unpack().String())
+	}
+	for _, p := range append(maze.Positions(), Position{-1, 0}, Position{0, 4}) {
+		if packed.Wall(p) != maze.Wall(p) {
+			t.Errorf("position %v: expected wall %v", p, maze.Wall(p))
+		}
+	}
+	if !reflect.DeepEqual(packed.Positions(), maze.Positions()) {
+		t.Errorf("expected positions %v but got %v", maze.Positions(), packed.Positions())
+	}
+	if n := packed.NumWalls(); n != 7 {
+		t.Errorf("expected 7 walls but got %d", n)
+	}
+	for i, expected := range []int{2, 2, 2, 1} {
+		if n := packed.RowWalls(i); n != expected {
+			t.Errorf("row %d: expected %d walls but got %d", i, expected, n)
+		}
+	}
+	for i, expected := range []int{2, 1, 3, 1} {
+		if n := packed.ColWalls(i); n != expected {
+			t.Errorf("col %d: expected %d walls but got %d", i, expected, n)
+		}
+	}
+
+	expectedGrid := oneHotGrid(maze, maze.Start, -1, -1, 6, 6)
+	if actual := oneHotGrid(packed, maze.Start, -1, -1, 6, 6); !reflect.DeepEqual(actual,
+		expectedGrid) {
+		t.Error("unexpected one-hot grid")
+	}
+
+	packed.SetWall(Position{3, 3}, true)
+	if !packed.Wall(Position{3, 3}) || packed.RowWalls(3) != 2 {
+		t.Error("SetWall had no effect")
+	}
+}
+
+func TestPackedMazeSolve(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	for i := 0; i < 10; i++ {
+		maze, err := (&PrimGenerator{}).GenerateRand(rng, 25, 71)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Braid(maze, 0.5, rng)
+		expected := Solve(maze)
+		actual := maze.Pack().Solve()
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %v but got %v", expected, actual)
+		}
+	}
+
+	maze, err := ParseMaze("Aw.\n.wx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solution := maze.Pack().Solve(); solution != nil {
+		t.Errorf("expected no solution but got %v", solution)
+	}
+
+	maze.End = maze.Start
+	if solution := maze.Pack().Solve(); !reflect.DeepEqual(solution, []Position{maze.Start}) {
+		t.Errorf("expected trivial solution but got %v", solution)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/mazenv"
+	"github.com/unixpickle/mazenv/render"
+)
+
+func main() {
+	var mazesPath string
+	var outPath string
+	var solve bool
+	var trajectoryPath string
+	var heatmapPath string
+	var style render.Style
+	colors := map[string]*color.Color{
+		"wall":       &style.Wall,
+		"space":      &style.Space,
+		"start":      &style.Start,
+		"end":        &style.End,
+		"path":       &style.Path,
+		"trajectory": &style.Trajectory,
+		"heat":       &style.Heat,
+	}
+	flag.StringVar(&mazesPath, "in", "", "file containing mazes (instead of stdin)")
+	flag.StringVar(&outPath, "out", "maze.png", "output file (.png or .svg)")
+	flag.IntVar(&style.CellSize, "cell", 10, "cell size in pixels")
+	flag.BoolVar(&solve, "solve", false, "draw the solution")
+	flag.StringVar(&trajectoryPath, "trajectory", "",
+		"file of 'row col' lines to draw as an agent trajectory")
+	flag.StringVar(&heatmapPath, "heatmap", "",
+		"file of per-cell values (row-major, whitespace-separated) to shade")
+	for name, dst := range colors {
+		flag.Var(&colorFlag{dst}, name+"-color", "color of "+name+" (e.g. #ff0000)")
+	}
+	flag.Parse()
+
+	ext := strings.ToLower(filepath.Ext(outPath))
+	if ext != ".png" && ext != ".svg" {
+		essentials.Die("unknown output extension: " + ext)
+	}
+
+	mazes, err := readMazes(mazesPath)
+	if err != nil {
+		essentials.Die(err)
+	}
+
+	var trajectory []mazenv.Position
+	if trajectoryPath != "" {
+		trajectory, err = readTrajectory(trajectoryPath)
+		if err != nil {
+			essentials.Die(err)
+		}
+	}
+	var heatmap []float64
+	if heatmapPath != "" {
+		heatmap, err = readHeatmap(heatmapPath)
+		if err != nil {
+			essentials.Die(err)
+		}
+	}
+
+	for i, maze := range mazes {
+		scene := &render.Scene{Maze: maze, Trajectory: trajectory}
+		if solve {
+			scene.Path = mazenv.Solve(maze)
+		}
+		scene.Heatmap = heatmap
+		path := outPath
+		if len(mazes) > 1 {
+			path = strings.TrimSuffix(outPath, filepath.Ext(outPath)) +
+				"_" + strconv.Itoa(i) + filepath.Ext(outPath)
+		}
+		if err := writeScene(path, scene, &style); err != nil {
+			essentials.Die(err)
+		}
+	}
+}
+
+func writeScene(path string, scene *render.Scene, style *render.Style) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(path)) == ".svg" {
+		return render.WriteSVG(f, scene, style)
+	}
+	img, err := render.Image(scene, style)
+	if err != nil {
+		return err
+	}
+	return png.Encode(f, img)
+}
+
+func readMazes(path string) ([]*mazenv.Maze, error) {
+	var data []byte
+	var err error
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "reading from standard input...")
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res []*mazenv.Maze
+	for _, chunk := range strings.Split(strings.Replace(string(data), "\r", "", -1), "\n\n") {
+		if strings.TrimSpace(chunk) == "" {
+			continue
+		}
+		maze, err := mazenv.ParseMaze(chunk)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, maze)
+	}
+	return res, nil
+}
+
+func readTrajectory(path string) ([]mazenv.Position, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res []mazenv.Position
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		} else if len(fields) != 2 {
+			return nil, errors.New("read trajectory: expected 'row col' but got: " + line)
+		}
+		row, err1 := strconv.Atoi(fields[0])
+		col, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			return nil, errors.New("read trajectory: invalid position: " + line)
+		}
+		res = append(res, mazenv.Position{Row: row, Col: col})
+	}
+	return res, nil
+}
+
+func readHeatmap(path string) ([]float64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res []float64
+	for _, field := range strings.Fields(string(data)) {
+		x, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, essentials.AddCtx("read heatmap", err)
+		}
+		res = append(res, x)
+	}
+	return res, nil
+}
+
+// colorFlag is a flag.Value for hex colors.
+type colorFlag struct {
+	dst *color.Color
+}
+
+func (c *colorFlag) String() string {
+	if c.dst == nil || *c.dst == nil {
+		return ""
+	}
+	r, g, b, _ := (*c.dst).RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+func (c *colorFlag) Set(s string) error {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 && len(s) != 8 {
+		return errors.New("expected #rrggbb or #rrggbbaa")
+	}
+	x, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return err
+	}
+	if len(s) == 6 {
+		x = x<<8 | 0xff
+	}
+	*c.dst = color.NRGBA{R: uint8(x >> 24), G: uint8(x >> 16), B: uint8(x >> 8), A: uint8(x)}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Package render draws mazes as PNG and SVG images.
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/mazenv"
+)
+
+// Scene is a maze along with optional overlays to draw on
+// top of it.
+type Scene struct {
+	Maze *mazenv.Maze
+
+	// Path is a sequence of positions, such as the result
+	// of mazenv.Solve, drawn as a line through the cells.
+	Path []mazenv.Position
+
+	// Trajectory is a sequence of positions visited by an
+	// agent, drawn as a thinner line than Path.
+	Trajectory []mazenv.Position
+
+	// Heatmap, if non-nil, assigns a value to every cell
+	// in the same order as Maze.Walls, such as a visit
+	// count or a value estimate.
+	// It must have exactly one value per cell.
+	// Spaces are shaded from Style.Space at the smallest
+	// value to Style.Heat at the largest value.
+	// NaN values are not shaded.
+	Heatmap []float64
+}
+
+// Style controls the appearance of rendered mazes.
+//
+// Zero fields are replaced by defaults.
+type Style struct {
+	// CellSize is the width and height of each cell in
+	// pixels.
+	//
+	// If 0, a default of 10 is used.
+	CellSize int
+
+	Wall       color.Color
+	Space      color.Color
+	Start      color.Color
+	End        color.Color
+	Path       color.Color
+	Trajectory color.Color
+	Heat       color.Color
+}
+
+// DefaultStyle is the Style used for any zero fields.
+var DefaultStyle = Style{
+	CellSize:   10,
+	Wall:       color.Black,
+	Space:      color.White,
+	Start:      color.RGBA{R: 0x00, G: 0xc0, B: 0x00, A: 0xff},
+	End:        color.RGBA{R: 0xe0, G: 0x00, B: 0x00, A: 0xff},
+	Path:       color.RGBA{R: 0x20, G: 0x60, B: 0xff, A: 0xff},
+	Trajectory: color.RGBA{R: 0xff, G: 0x90, B: 0x00, A: 0xff},
+	Heat:       color.RGBA{R: 0xff, G: 0xd0, B: 0x00, A: 0xff},
+}
+
+// withDefaults returns a copy of the style with the zero
+// fields filled in.
+// It accepts a nil receiver.
+func (s *Style) withDefaults() *Style {
+	res := DefaultStyle
+	if s == nil {
+		return &res
+	}
+	if s.CellSize != 0 {
+		res.CellSize = s.CellSize
+	}
+	for _, pair := range []struct {
+		dst *color.Color
+		src color.Color
+	}{
+		{&res.Wall, s.Wall},
+		{&res.Space, s.Space},
+		{&res.Start, s.Start},
+		{&res.End, s.End},
+		{&res.Path, s.Path},
+		{&res.Trajectory, s.Trajectory},
+		{&res.Heat, s.Heat},
+	} {
+		if pair.src != nil {
+			*pair.dst = pair.src
+		}
+	}
+	return &res
+}
+
+// validateScene checks that the maze and heatmap have
+// consistent sizes.
+func validateScene(s *Scene) error {
+	m := s.Maze
+	if m.Rows < 0 || m.Cols < 0 || len(m.Walls) != m.Rows*m.Cols {
+		return errors.New("maze dimensions do not match walls")
+	}
+	if s.Heatmap != nil && len(s.Heatmap) != len(m.Walls) {
+		return fmt.Errorf("heatmap has %d values but maze has %d cells", len(s.Heatmap),
+			len(m.Walls))
+	}
+	return nil
+}
+
+// cellColors computes the fill color of every cell,
+// including the heatmap.
+func cellColors(s *Scene, style *Style) []color.Color {
+	m := s.Maze
+	minHeat, maxHeat := heatRange(s)
+	res := make([]color.Color, len(m.Walls))
+	for i, p := range m.Positions() {
+		switch {
+		case p == m.Start:
+			res[i] = style.Start
+		case p == m.End:
+			res[i] = style.End
+		case m.Walls[i]:
+			res[i] = style.Wall
+		case s.Heatmap != nil && !math.IsNaN(s.Heatmap[i]):
+			frac := 1.0
+			if maxHeat > minHeat {
+				frac = (s.Heatmap[i] - minHeat) / (maxHeat - minHeat)
+			}
+			res[i] = interpolate(style.Space, style.Heat, frac)
+		default:
+			res[i] = style.Space
+		}
+	}
+	return res
+}
+
+// heatRange finds the range of heatmap values for spaces.
+func heatRange(s *Scene) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for i, x := range s.Heatmap {
+		if !s.Maze.Walls[i] && !math.IsNaN(x) {
+			min = math.Min(min, x)
+			max = math.Max(max, x)
+		}
+	}
+	return
+}
+
+func interpolate(c1, c2 color.Color, frac float64) color.Color {
+	r1, g1, b1, a1 := c1.RGBA()
+	r2, g2, b2, a2 := c2.RGBA()
+	mix := func(x1, x2 uint32) uint16 {
+		return uint16(float64(x1) + frac*(float64(x2)-float64(x1)))
+	}
+	return color.RGBA64{R: mix(r1, r2), G: mix(g1, g2), B: mix(b1, b2), A: mix(a1, a2)}
+}
+
+// Image draws a scene as an image.
+//
+// The style may be nil to use DefaultStyle.
+func Image(s *Scene, style *Style) (img *image.RGBA, err error) {
+	defer essentials.AddCtxTo("render image", &err)
+	if err := validateScene(s); err != nil {
+		return nil, err
+	}
+	style = style.withDefaults()
+	m, size := s.Maze, style.CellSize
+	img = image.NewRGBA(image.Rect(0, 0, m.Cols*size, m.Rows*size))
+	for i, c := range cellColors(s, style) {
+		row, col := i/m.Cols, i%m.Cols
+		fillRect(img, image.Rect(col*size, row*size, (col+1)*size, (row+1)*size), c)
+	}
+	drawLine(img, s.Path, size, pathWidth(size), style.Path)
+	drawLine(img, s.Trajectory, size, trajectoryWidth(size), style.Trajectory)
+	return img, nil
+}
+
+func pathWidth(cellSize int) int {
+	return essentials.MaxInt(1, cellSize*2/5)
+}
+
+func trajectoryWidth(cellSize int) int {
+	return essentials.MaxInt(1, cellSize/5)
+}
+
+// drawLine draws a line of the given width through the
+// centers of the cells.
+func drawLine(img *image.RGBA, points []mazenv.Position, cellSize, width int,
+	c color.Color) {
+	center := func(p mazenv.Position) (float64, float64) {
+		return (float64(p.Col) + 0.5) * float64(cellSize),
+			(float64(p.Row) + 0.5) * float64(cellSize)
+	}
+	dot := func(x, y float64) {
+		x0, y0 := int(math.Round(x-float64(width)/2)), int(math.Round(y-float64(width)/2))
+		fillRect(img, image.Rect(x0, y0, x0+width, y0+width), c)
+	}
+	for i, p := range points {
+		x, y := center(p)
+		dot(x, y)
+		if i == 0 {
+			continue
+		}
+		prevX, prevY := center(points[i-1])
+		steps := int(math.Ceil(math.Max(math.Abs(x-prevX), math.Abs(y-prevY))))
+		for j := 1; j < steps; j++ {
+			frac := float64(j) / float64(steps)
+			dot(prevX+frac*(x-prevX), prevY+frac*(y-prevY))
+		}
+	}
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
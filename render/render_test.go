@@ -0,0 +1,110 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/unixpickle/mazenv"
+)
+
+func TestImage(t *testing.T) {
+	maze, err := mazenv.ParseMaze("A..\nww.\nx..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scene := &Scene{
+		Maze:    maze,
+		Path:    mazenv.Solve(maze),
+		Heatmap: []float64{0, 1, 2, 0, 0, 3, 0, 4, math.NaN()},
+	}
+	style := &Style{CellSize: 10}
+	img, err := Image(scene, style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 30 || img.Bounds().Dy() != 30 {
+		t.Fatalf("unexpected bounds: %v", img.Bounds())
+	}
+
+	expected := []struct {
+		X, Y  int
+		Color color.Color
+	}{
+		{1, 1, DefaultStyle.Start},
+		{1, 21, DefaultStyle.End},
+		{1, 11, DefaultStyle.Wall},
+		{28, 28, DefaultStyle.Space},
+		{15, 5, DefaultStyle.Path},
+		{25, 15, DefaultStyle.Path},
+		{11, 1, interpolate(DefaultStyle.Space, DefaultStyle.Heat, 0.25)},
+		{18, 28, DefaultStyle.Heat},
+	}
+	for _, e := range expected {
+		if !colorsEqual(img.At(e.X, e.Y), e.Color) {
+			t.Errorf("pixel (%d, %d): expected %v but got %v", e.X, e.Y, e.Color,
+				img.At(e.X, e.Y))
+		}
+	}
+
+	scene = &Scene{Maze: maze, Trajectory: []mazenv.Position{{Row: 0, Col: 0}, {Row: 0, Col: 1}}}
+	img, err = Image(scene, &Style{CellSize: 10, Trajectory: color.White})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !colorsEqual(img.At(5, 5), color.White) || !colorsEqual(img.At(1, 1), DefaultStyle.Start) {
+		t.Error("unexpected trajectory rendering")
+	}
+
+	scene = &Scene{Maze: maze, Heatmap: []float64{1, 2, 3}}
+	if _, err := Image(scene, nil); err == nil {
+		t.Error("expected error for short heatmap")
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	maze, err := mazenv.ParseMaze("A..\nww.\nx..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scene := &Scene{Maze: maze, Path: mazenv.Solve(maze)}
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, scene, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{}
+	dec := xml.NewDecoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			counts[start.Name.Local]++
+		}
+	}
+	// Background, start, the merged wall row, and end.
+	expected := map[string]int{"svg": 1, "rect": 4, "polyline": 1}
+	for name, count := range expected {
+		if counts[name] != count {
+			t.Errorf("expected %d %s elements but got %d", count, name, counts[name])
+		}
+	}
+
+	scene.Heatmap = make([]float64, 10)
+	if err := WriteSVG(&buf, scene, nil); err == nil {
+		t.Error("expected error for long heatmap")
+	}
+}
+
+func colorsEqual(c1, c2 color.Color) bool {
+	r1, g1, b1, a1 := c1.RGBA()
+	r2, g2, b2, a2 := c2.RGBA()
+	return r1>>8 == r2>>8 && g1>>8 == g2>>8 && b1>>8 == b2>>8 && a1>>8 == a2>>8
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/mazenv"
+)
+
+// WriteSVG draws a scene as an SVG document.
+//
+// The style may be nil to use DefaultStyle.
+func WriteSVG(w io.Writer, s *Scene, style *Style) (err error) {
+	defer essentials.AddCtxTo("write SVG", &err)
+	if err := validateScene(s); err != nil {
+		return err
+	}
+	style = style.withDefaults()
+	m, size := s.Maze, style.CellSize
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+		`viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		m.Cols*size, m.Rows*size, m.Cols*size, m.Rows*size)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" %s/>`+"\n", svgFill(style.Space))
+
+	// Adjacent cells of the same color in a row are merged
+	// into a single rectangle to keep the output small.
+	colors := cellColors(s, style)
+	for row := 0; row < m.Rows; row++ {
+		for col := 0; col < m.Cols; {
+			c := colors[row*m.Cols+col]
+			end := col + 1
+			for end < m.Cols && colors[row*m.Cols+end] == c {
+				end++
+			}
+			if c != style.Space {
+				fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" %s/>`+"\n",
+					col*size, row*size, (end-col)*size, size, svgFill(c))
+			}
+			col = end
+		}
+	}
+
+	writeSVGLine(bw, s.Path, size, pathWidth(size), style.Path)
+	writeSVGLine(bw, s.Trajectory, size, trajectoryWidth(size), style.Trajectory)
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+func writeSVGLine(w io.Writer, points []mazenv.Position, cellSize, width int,
+	c color.Color) {
+	if len(points) == 0 {
+		return
+	}
+	var coords []string
+	for _, p := range points {
+		coords = append(coords, fmt.Sprintf("%g,%g",
+			(float64(p.Col)+0.5)*float64(cellSize), (float64(p.Row)+0.5)*float64(cellSize)))
+	}
+	fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-opacity="%g" `+
+		`stroke-width="%d" stroke-linecap="square" stroke-linejoin="miter"/>`+"\n",
+		strings.Join(coords, " "), svgColor(c), svgOpacity(c), width)
+}
+
+func svgFill(c color.Color) string {
+	return fmt.Sprintf(`fill="%s" fill-opacity="%g"`, svgColor(c), svgOpacity(c))
+}
+
+func svgColor(c color.Color) string {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", nrgba.R, nrgba.G, nrgba.B)
+}
+
+func svgOpacity(c color.Color) float64 {
+	return float64(color.NRGBAModel.Convert(c).(color.NRGBA).A) / 0xff
+}
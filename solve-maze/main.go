@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -12,24 +13,80 @@ import (
 	"github.com/unixpickle/mazenv"
 )
 
+var Solvers = map[string]mazenv.Solver{
+	"bfs":           &mazenv.BFSSolver{},
+	"astar":         &mazenv.AStarSolver{},
+	"bidirectional": &mazenv.BidirectionalSolver{},
+}
+
 func main() {
 	var mazesPath string
 	var lengthOnly bool
+	var format string
+	var style string
+	var dedupe bool
+	var solverName string
 	flag.StringVar(&mazesPath, "in", "", "file containing mazes (instead of stdin)")
 	flag.BoolVar(&lengthOnly, "length", false, "only print the solution length")
+	flag.StringVar(&format, "format", "text", "input format (text, json, jsonl, or binary)")
+	flag.StringVar(&style, "style", "",
+		"draw each maze with its solution (plain, block, or ansi) instead of listing positions")
+	flag.StringVar(&solverName, "solver", "bfs", "search algorithm (bfs, astar, or bidirectional)")
+	flag.BoolVar(&dedupe, "dedupe", false,
+		"skip mazes which are rotations or reflections of earlier ones")
 	flag.Parse()
 
-	for maze := range readMazes(mazesPath) {
-		solution := mazenv.Solve(maze)
+	switch format {
+	case "text", "json", "jsonl", "binary":
+	default:
+		essentials.Die("unknown format: " + format)
+	}
+
+	solver, ok := Solvers[solverName]
+	if !ok {
+		essentials.Die("unknown solver: " + solverName)
+	}
+
+	var textOpts *mazenv.TextOptions
+	switch style {
+	case "":
+	case "plain":
+		textOpts = &mazenv.TextOptions{}
+	case "block":
+		textOpts = &mazenv.TextOptions{Block: true}
+	case "ansi":
+		textOpts = &mazenv.TextOptions{Block: true, Color: true}
+	default:
+		essentials.Die("unknown style: " + style)
+	}
+
+	seen := map[[32]byte]bool{}
+	for maze := range readMazes(mazesPath, format) {
+		if dedupe {
+			hash, err := maze.CanonicalHash()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "skipping maze:", err)
+				continue
+			}
+			if seen[hash] {
+				continue
+			}
+			seen[hash] = true
+		}
+		solution := solver.Solve(maze)
 		if lengthOnly {
 			fmt.Println(len(solution))
+		} else if textOpts != nil {
+			textOpts.Path = solution
+			fmt.Println(maze.Text(textOpts))
+			fmt.Println()
 		} else {
 			fmt.Println(solution)
 		}
 	}
 }
 
-func readMazes(path string) <-chan *mazenv.Maze {
+func readMazes(path, format string) <-chan *mazenv.Maze {
 	res := make(chan *mazenv.Maze, 1)
 
 	go func() {
@@ -39,6 +96,41 @@ func readMazes(path string) <-chan *mazenv.Maze {
 		essentials.Must(err)
 		defer reader.Close()
 
+		switch format {
+		case "json":
+			dec := json.NewDecoder(reader)
+			for {
+				var maze mazenv.Maze
+				err := dec.Decode(&maze)
+				if err == io.EOF {
+					return
+				}
+				essentials.Must(err)
+				res <- &maze
+			}
+		case "jsonl":
+			dataset := mazenv.NewDatasetReader(reader)
+			for {
+				entry, err := dataset.Read()
+				if err == io.EOF {
+					return
+				}
+				essentials.Must(err)
+				res <- entry.Maze
+			}
+		case "binary":
+			binReader, err := mazenv.NewBinaryReader(reader)
+			essentials.Must(err)
+			for {
+				maze, err := binReader.Read()
+				if err == io.EOF {
+					return
+				}
+				essentials.Must(err)
+				res <- maze
+			}
+		}
+
 		br := bufio.NewReader(reader)
 
 		var curMaze string
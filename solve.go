@@ -1,38 +1,302 @@
 package mazenv
 
+import "container/heap"
+
+// A Solver finds optimal solutions to mazes.
+//
+// Solutions are represented as a list of positions that
+// comprise the solution, including the start and end.
+// If no solution is found, nil is returned.
+//
+// Different solvers may return different solutions when
+// more than one is optimal.
+type Solver interface {
+	Solve(g Grid) []Position
+}
+
 // Solve finds an optimal solution to the maze.
 //
 // The solution is represented as a list of positions that
 // comprise the solution, including the start and end.
 //
 // If no solution is found, nil is returned.
+//
+// This is equivalent to using a BFSSolver.
 func Solve(m *Maze) []Position {
-	queue := []searchNode{{Path: []Position{m.Start}}}
-	visited := map[Position]bool{m.Start: true}
+	return (&BFSSolver{}).Solve(m)
+}
+
+// solveOffsets lists the directions to search in the
+// same order as the neighbors function.
+var solveOffsets = [4]Position{{Row: -1}, {Row: 1}, {Col: -1}, {Col: 1}}
+
+// BFSSolver is a Solver which uses breadth-first search.
+//
+// Each cell stores a pointer to the cell it was reached
+// from, so memory usage is linear in the size of the
+// maze.
+type BFSSolver struct{}
+
+// Solve finds an optimal solution to the maze.
+func (b *BFSSolver) Solve(g Grid) []Position {
+	shape, start, end := newSearchGrid(g)
+	if !shape.InBounds(start) {
+		return nil
+	} else if start == end {
+		return []Position{start}
+	}
+	parents := shape.NewParents()
+	startIdx := shape.CellIndex(start)
+	parents[startIdx] = int32(startIdx)
+	queue := []int32{int32(startIdx)}
 	for len(queue) > 0 {
-		node := queue[0]
+		cell := int(queue[0])
 		queue = queue[1:]
-		for _, neighbor := range neighboringSpaces(m, node.Pos()) {
-			if neighbor == m.End {
-				return node.Add(m.End).Path
+		pos := shape.Position(cell)
+		for _, off := range solveOffsets {
+			neighbor := addOffset(pos, off)
+			if g.Wall(neighbor) {
+				continue
 			}
-			if !visited[neighbor] {
-				visited[neighbor] = true
-				queue = append(queue, node.Add(neighbor))
+			if neighbor == end {
+				return append(shape.TracePath(parents, cell), end)
+			}
+			if idx := shape.CellIndex(neighbor); parents[idx] < 0 {
+				parents[idx] = int32(cell)
+				queue = append(queue, int32(idx))
 			}
 		}
 	}
 	return nil
 }
 
-type searchNode struct {
-	Path []Position
+// AStarSolver is a Solver which uses A* search with the
+// Manhattan distance to the end as a heuristic.
+//
+// It usually explores fewer cells than a BFSSolver when
+// the end is reachable without large detours.
+type AStarSolver struct{}
+
+// Solve finds an optimal solution to the maze.
+func (a *AStarSolver) Solve(g Grid) []Position {
+	shape, start, end := newSearchGrid(g)
+	if !shape.InBounds(start) {
+		return nil
+	} else if start == end {
+		return []Position{start}
+	}
+	parents := shape.NewParents()
+	costs := make([]int32, shape.Rows*shape.Cols)
+	startIdx := shape.CellIndex(start)
+	parents[startIdx] = int32(startIdx)
+	queue := &astarQueue{{Cell: startIdx, Estimate: manhattanDistance(start, end)}}
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(astarNode)
+		if node.Cost > int(costs[node.Cell]) {
+			// A shorter path to this cell was found after it
+			// was pushed.
+			continue
+		}
+		pos := shape.Position(node.Cell)
+		if pos == end {
+			return shape.TracePath(parents, node.Cell)
+		}
+		for _, off := range solveOffsets {
+			neighbor := addOffset(pos, off)
+			if g.Wall(neighbor) {
+				continue
+			}
+			idx, cost := shape.CellIndex(neighbor), node.Cost+1
+			if parents[idx] < 0 || cost < int(costs[idx]) {
+				parents[idx] = int32(node.Cell)
+				costs[idx] = int32(cost)
+				heap.Push(queue, astarNode{
+					Cell:     idx,
+					Cost:     cost,
+					Estimate: cost + manhattanDistance(neighbor, end),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// BidirectionalSolver is a Solver which runs breadth-first
+// searches from the start and the end at the same time,
+// until they meet in the middle.
+//
+// It usually explores fewer cells than a BFSSolver in
+// mazes with many branches.
+type BidirectionalSolver struct{}
+
+// Solve finds an optimal solution to the maze.
+func (b *BidirectionalSolver) Solve(g Grid) []Position {
+	shape, start, end := newSearchGrid(g)
+	if !shape.InBounds(start) {
+		return nil
+	} else if start == end {
+		return []Position{start}
+	} else if g.Wall(end) {
+		return nil
+	}
+
+	type searchSide struct {
+		Parents  []int32
+		Dists    []int32
+		Frontier []int
+	}
+	var sides [2]*searchSide
+	for i, p := range []Position{start, end} {
+		idx := shape.CellIndex(p)
+		sides[i] = &searchSide{
+			Parents:  shape.NewParents(),
+			Dists:    make([]int32, shape.Rows*shape.Cols),
+			Frontier: []int{idx},
+		}
+		sides[i].Parents[idx] = int32(idx)
+	}
+
+	for len(sides[0].Frontier) > 0 && len(sides[1].Frontier) > 0 {
+		// Expand the smaller frontier by one level.
+		cur := 0
+		if len(sides[1].Frontier) < len(sides[0].Frontier) {
+			cur = 1
+		}
+		side, other := sides[cur], sides[1-cur]
+
+		meeting, meetingDist := -1, int32(0)
+		var next []int
+		for _, cell := range side.Frontier {
+			pos := shape.Position(cell)
+			for _, off := range solveOffsets {
+				neighbor := addOffset(pos, off)
+				if g.Wall(neighbor) {
+					continue
+				}
+				idx := shape.CellIndex(neighbor)
+				if side.Parents[idx] >= 0 {
+					continue
+				}
+				side.Parents[idx] = int32(cell)
+				side.Dists[idx] = side.Dists[cell] + 1
+				next = append(next, idx)
+				if other.Parents[idx] >= 0 {
+					// Every meeting on this level must be
+					// considered to find the shortest path.
+					if dist := side.Dists[idx] + other.Dists[idx]; meeting < 0 ||
+						dist < meetingDist {
+						meeting, meetingDist = idx, dist
+					}
+				}
+			}
+		}
+		side.Frontier = next
+
+		if meeting >= 0 {
+			res := shape.TracePath(sides[0].Parents, meeting)
+			for cur := meeting; cur != int(sides[1].Parents[cur]); {
+				cur = int(sides[1].Parents[cur])
+				res = append(res, shape.Position(cur))
+			}
+			return res
+		}
+	}
+	return nil
+}
+
+// searchGrid maps the positions of a Grid to and from
+// row-major cell indices for the solvers.
+type searchGrid struct {
+	Rows int
+	Cols int
+}
+
+func newSearchGrid(g Grid) (shape searchGrid, start, end Position) {
+	shape.Rows, shape.Cols = g.Size()
+	start, end = g.Endpoints()
+	return
+}
+
+func (s searchGrid) InBounds(pos Position) bool {
+	return pos.Row >= 0 && pos.Row < s.Rows &&
+		pos.Col >= 0 && pos.Col < s.Cols
+}
+
+func (s searchGrid) CellIndex(pos Position) int {
+	return pos.Row*s.Cols + pos.Col
+}
+
+func (s searchGrid) Position(cell int) Position {
+	return Position{Row: cell / s.Cols, Col: cell % s.Cols}
+}
+
+// NewParents creates a list of parent pointers for every
+// cell, all of which are -1 (unvisited).
+//
+// Pointers, like the distances kept by the solvers, are
+// 32 bits to keep the search small compared to a
+// PackedMaze.
+func (s searchGrid) NewParents() []int32 {
+	res := make([]int32, s.Rows*s.Cols)
+	for i := range res {
+		res[i] = -1
+	}
+	return res
+}
+
+// TracePath follows parent pointers from a cell back to
+// the root of the search, which is its own parent.
+//
+// The result starts at the root and ends at the cell.
+func (s searchGrid) TracePath(parents []int32, cell int) []Position {
+	var res []Position
+	for {
+		res = append(res, s.Position(cell))
+		if int(parents[cell]) == cell {
+			break
+		}
+		cell = int(parents[cell])
+	}
+	for i := 0; i < len(res)/2; i++ {
+		res[i], res[len(res)-1-i] = res[len(res)-1-i], res[i]
+	}
+	return res
+}
+
+type astarNode struct {
+	Cell     int
+	Cost     int
+	Estimate int
+}
+
+// astarQueue is a heap of nodes ordered by their
+// estimated total cost.
+// Ties are broken in favor of longer paths, which are
+// closer to the end.
+type astarQueue []astarNode
+
+func (a astarQueue) Len() int {
+	return len(a)
+}
+
+func (a astarQueue) Less(i, j int) bool {
+	if a[i].Estimate == a[j].Estimate {
+		return a[i].Cost > a[j].Cost
+	}
+	return a[i].Estimate < a[j].Estimate
+}
+
+func (a astarQueue) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
 }
 
-func (s searchNode) Add(p Position) searchNode {
-	return searchNode{Path: append(append([]Position{}, s.Path...), p)}
+func (a *astarQueue) Push(x interface{}) {
+	*a = append(*a, x.(astarNode))
 }
 
-func (s searchNode) Pos() Position {
-	return s.Path[len(s.Path)-1]
+func (a *astarQueue) Pop() interface{} {
+	old := *a
+	res := old[len(old)-1]
+	*a = old[:len(old)-1]
+	return res
 }
@@ -1,6 +1,7 @@
 package mazenv
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -34,3 +35,139 @@ func TestSolve(t *testing.T) {
 		t.Errorf("expected %v but got %v", expected, actual)
 	}
 }
+
+func TestSolveMatchesBaseline(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	for i := 0; i < 20; i++ {
+		maze, err := (&PrimGenerator{}).GenerateRand(rng, 31, 41)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Braid(maze, rng.Float64(), rng)
+		expected := solveBaseline(maze)
+		if actual := Solve(maze); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestSolvers(t *testing.T) {
+	solvers := map[string]Solver{
+		"BFS":           &BFSSolver{},
+		"AStar":         &AStarSolver{},
+		"Bidirectional": &BidirectionalSolver{},
+	}
+	rng := rand.New(rand.NewSource(1337))
+	var mazes []*Maze
+	for i := 0; i < 20; i++ {
+		maze, err := (&CaveGenerator{}).GenerateRand(rng, 25, 35)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mazes = append(mazes, maze)
+		maze, err = (&PrimGenerator{}).GenerateRand(rng, 25, 35)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Braid(maze, rng.Float64(), rng)
+		mazes = append(mazes, maze)
+	}
+	unsolvable, err := ParseMaze("ww..ww\nxw....\nwAwwww")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mazes = append(mazes, unsolvable)
+	trivial := testingMaze()
+	trivial.End = trivial.Start
+	outside := testingMaze()
+	outside.Start, outside.End = Position{-1, 0}, Position{-1, 0}
+	mazes = append(mazes, trivial, outside)
+
+	for name, solver := range solvers {
+		t.Run(name, func(t *testing.T) {
+			for _, maze := range mazes {
+				expected := solveBaseline(maze)
+				if maze.Start == maze.End && maze.InBounds(maze.Start) {
+					// The baseline walks away from the start
+					// and back again.
+					expected = []Position{maze.Start}
+				}
+				actual := solver.Solve(maze)
+				if expected == nil {
+					if actual != nil {
+						t.Errorf("found false solution %v", actual)
+					}
+					continue
+				}
+				if len(actual) != len(expected) {
+					t.Fatalf("expected length %d but got %d", len(expected), len(actual))
+				}
+				if actual[0] != maze.Start || actual[len(actual)-1] != maze.End {
+					t.Fatalf("bad endpoints: %v", actual)
+				}
+				for i := 1; i < len(actual); i++ {
+					if manhattanDistance(actual[i-1], actual[i]) != 1 || maze.Wall(actual[i]) {
+						t.Fatalf("invalid step from %v to %v", actual[i-1], actual[i])
+					}
+				}
+				if packed := solver.Solve(maze.Pack()); !reflect.DeepEqual(packed, actual) {
+					t.Fatalf("expected %v for packed maze but got %v", actual, packed)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSolvers(b *testing.B) {
+	maze, err := GenerateSeed(&PrimGenerator{}, 1337, 301, 301)
+	if err != nil {
+		b.Fatal(err)
+	}
+	solvers := map[string]func(m *Maze) []Position{
+		"Baseline":      solveBaseline,
+		"BFS":           Solve,
+		"AStar":         func(m *Maze) []Position { return (&AStarSolver{}).Solve(m) },
+		"Bidirectional": func(m *Maze) []Position { return (&BidirectionalSolver{}).Solve(m) },
+	}
+	for _, name := range []string{"Baseline", "BFS", "AStar", "Bidirectional"} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				solvers[name](maze)
+			}
+		})
+	}
+}
+
+// solveBaseline is the original implementation of Solve,
+// which copies the path for every node in the search.
+func solveBaseline(m *Maze) []Position {
+	queue := []searchNode{{Path: []Position{m.Start}}}
+	visited := map[Position]bool{m.Start: true}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range neighboringSpaces(m, node.Pos()) {
+			if neighbor == m.End {
+				return node.Add(m.End).Path
+			}
+			if !visited[neighbor] {
+				visited[neighbor] = true
+				queue = append(queue, node.Add(neighbor))
+			}
+		}
+	}
+	return nil
+}
+
+type searchNode struct {
+	Path []Position
+}
+
+func (s searchNode) Add(p Position) searchNode {
+	return searchNode{Path: append(append([]Position{}, s.Path...), p)}
+}
+
+func (s searchNode) Pos() Position {
+	return s.Path[len(s.Path)-1]
+}
@@ -0,0 +1,165 @@
+package mazenv
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// Rect is a rectangular region of a grid.
+type Rect struct {
+	Row  int
+	Col  int
+	Rows int
+	Cols int
+}
+
+// Crop creates a new maze from a rectangle of the grid.
+//
+// The rectangle must be within bounds.
+// The start and end are moved along with the rest of the
+// grid, so they may be out of bounds if they are not in
+// the rectangle.
+func (m *Maze) Crop(r Rect) *Maze {
+	if r.Rows < 0 || r.Cols < 0 || r.Row < 0 || r.Col < 0 ||
+		r.Row+r.Rows > m.Rows || r.Col+r.Cols > m.Cols {
+		panic("crop rectangle out of bounds")
+	}
+	offset := func(p Position) Position {
+		return Position{Row: p.Row - r.Row, Col: p.Col - r.Col}
+	}
+	res := &Maze{
+		Rows:  r.Rows,
+		Cols:  r.Cols,
+		Start: offset(m.Start),
+		End:   offset(m.End),
+		Walls: make([]bool, 0, r.Rows*r.Cols),
+	}
+	for row := r.Row; row < r.Row+r.Rows; row++ {
+		start := m.CellIndex(Position{Row: row, Col: r.Col})
+		res.Walls = append(res.Walls, m.Walls[start:start+r.Cols]...)
+	}
+	return res
+}
+
+// Unbordered creates a new maze by removing the outermost
+// cells of the grid.
+// It is the inverse of Bordered.
+//
+// The maze must have at least two rows and columns.
+func (m *Maze) Unbordered() *Maze {
+	return m.Crop(Rect{Row: 1, Col: 1, Rows: m.Rows - 2, Cols: m.Cols - 2})
+}
+
+// Stitch creates a large maze out of a grid of tiles.
+//
+// Tiles in the same row must have the same number of
+// rows, and tiles in the same column must have the same
+// number of columns.
+// Neighboring tiles are separated by a line of walls, in
+// which a random door is opened between every pair of
+// neighboring tiles.
+// For mazes with cells at even coordinates, such as the
+// ones from BacktrackerGenerator, this keeps the cells of
+// the result at even coordinates.
+//
+// The start is taken from the top-left tile, and the end
+// from the bottom-right tile.
+// An error is returned if either is outside of its tile,
+// as can happen after Crop.
+// If either is a wall, it is opened.
+//
+// Doors are only placed where both sides are spaces.
+// If this leaves the maze disconnected, more walls are
+// opened to connect it, so the result is always solvable.
+//
+// If rng is nil, the global source from math/rand is used.
+func Stitch(grid [][]*Maze, rng *rand.Rand) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	if len(grid) == 0 || len(grid[0]) == 0 {
+		return nil, errors.New("stitch: empty grid")
+	}
+	rowOffsets := make([]int, len(grid)+1)
+	colOffsets := make([]int, len(grid[0])+1)
+	for i, tiles := range grid {
+		if len(tiles) != len(grid[0]) {
+			return nil, errors.New("stitch: rows have different numbers of tiles")
+		}
+		for j, tile := range tiles {
+			if tile.Rows != grid[i][0].Rows {
+				return nil, errors.New("stitch: tiles in a row have different heights")
+			} else if tile.Cols != grid[0][j].Cols {
+				return nil, errors.New("stitch: tiles in a column have different widths")
+			}
+		}
+		rowOffsets[i+1] = rowOffsets[i] + tiles[0].Rows + 1
+	}
+	for j, tile := range grid[0] {
+		colOffsets[j+1] = colOffsets[j] + tile.Cols + 1
+	}
+	first, last := grid[0][0], grid[len(grid)-1][len(grid[0])-1]
+	if !first.InBounds(first.Start) {
+		return nil, errors.New("stitch: start is outside of the top-left tile")
+	} else if !last.InBounds(last.End) {
+		return nil, errors.New("stitch: end is outside of the bottom-right tile")
+	}
+
+	res := &Maze{
+		Rows: rowOffsets[len(grid)] - 1,
+		Cols: colOffsets[len(grid[0])] - 1,
+	}
+	res.Start = first.Start
+	res.End = Position{
+		Row: last.End.Row + rowOffsets[len(grid)-1],
+		Col: last.End.Col + colOffsets[len(grid[0])-1],
+	}
+	res.Walls = make([]bool, res.Rows*res.Cols)
+	for i := range res.Walls {
+		res.Walls[i] = true
+	}
+	for i, tiles := range grid {
+		for j, tile := range tiles {
+			for _, p := range tile.Positions() {
+				dst := Position{Row: p.Row + rowOffsets[i], Col: p.Col + colOffsets[j]}
+				res.Walls[res.CellIndex(dst)] = tile.Wall(p)
+			}
+		}
+	}
+	res.Walls[res.CellIndex(res.Start)] = false
+	res.Walls[res.CellIndex(res.End)] = false
+
+	// Each door is a wall in a separator with spaces on
+	// both sides of it.
+	openDoor := func(candidates []Position, step Position) {
+		var doors []Position
+		for _, p := range candidates {
+			before := Position{Row: p.Row - step.Row, Col: p.Col - step.Col}
+			after := Position{Row: p.Row + step.Row, Col: p.Col + step.Col}
+			if !res.Wall(before) && !res.Wall(after) {
+				doors = append(doors, p)
+			}
+		}
+		if len(doors) > 0 {
+			res.Walls[res.CellIndex(doors[rng.Intn(len(doors))])] = false
+		}
+	}
+	for i, tiles := range grid {
+		for j := range tiles {
+			if j+1 < len(tiles) {
+				var candidates []Position
+				for row := rowOffsets[i]; row < rowOffsets[i+1]-1; row++ {
+					candidates = append(candidates, Position{Row: row, Col: colOffsets[j+1] - 1})
+				}
+				openDoor(candidates, Position{Col: 1})
+			}
+			if i+1 < len(grid) {
+				var candidates []Position
+				for col := colOffsets[j]; col < colOffsets[j+1]-1; col++ {
+					candidates = append(candidates, Position{Row: rowOffsets[i+1] - 1, Col: col})
+				}
+				openDoor(candidates, Position{Row: 1})
+			}
+		}
+	}
+	connectRegions(res, nil)
+	return res, nil
+}
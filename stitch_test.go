@@ -0,0 +1,114 @@
+package mazenv
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMazeCrop(t *testing.T) {
+	maze := testingMaze()
+	cropped := maze.Crop(Rect{Row: 1, Col: 1, Rows: 2, Cols: 3})
+	expected := &Maze{
+		Rows:  2,
+		Cols:  3,
+		Start: Position{1, 0},
+		End:   Position{0, 2},
+		Walls: []bool{false, true, false, false, true, true},
+	}
+	if cropped.String() != expected.String() || cropped.Start != expected.Start ||
+		cropped.End != expected.End {
+		t.Errorf("expected %#v but got %#v", expected.String(), cropped.String())
+	}
+
+	unbordered := maze.Bordered().Unbordered()
+	if unbordered.String() != maze.String() || unbordered.Start != maze.Start ||
+		unbordered.End != maze.End {
+		t.Errorf("expected %#v but got %#v", maze.String(), unbordered.String())
+	}
+}
+
+func TestStitch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	sizes := [][2]int{{7, 9}, {5, 11}}
+	var grid [][]*Maze
+	for i := 0; i < 2; i++ {
+		var row []*Maze
+		for j := 0; j < 3; j++ {
+			tile, err := (&BacktrackerGenerator{}).GenerateRand(rng, sizes[i][0],
+				[]int{9, 5, 7}[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			row = append(row, tile)
+		}
+		grid = append(grid, row)
+	}
+
+	maze, err := Stitch(grid, rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maze.Rows != 13 || maze.Cols != 23 {
+		t.Fatalf("unexpected dimensions %dx%d", maze.Rows, maze.Cols)
+	}
+	if maze.Start != grid[0][0].Start {
+		t.Errorf("unexpected start %v", maze.Start)
+	}
+	if end := grid[1][2].End; maze.End != (Position{end.Row + 8, end.Col + 16}) {
+		t.Errorf("unexpected end %v", maze.End)
+	}
+	tile := maze.Crop(Rect{Row: 8, Col: 10, Rows: 5, Cols: 5})
+	if !reflect.DeepEqual(tile.Walls, grid[1][1].Walls) {
+		t.Errorf("tile was modified: %#v", maze.String())
+	}
+
+	// Lattice mazes always have room for doors, so each
+	// pair of tiles is joined by exactly one door.
+	var doors int
+	for _, p := range maze.Positions() {
+		if (p.Row == 7 || p.Col == 9 || p.Col == 15) && !maze.Wall(p) {
+			doors++
+		}
+	}
+	if doors != 7 {
+		t.Errorf("expected 7 doors but got %d: %#v", doors, maze.String())
+	}
+	if n := len(spaceRegions(maze)); n != 1 {
+		t.Errorf("expected 1 region but got %d", n)
+	}
+
+	grid[1] = grid[1][:2]
+	if _, err := Stitch(grid, rng); err == nil {
+		t.Error("expected error for ragged grid")
+	}
+}
+
+func TestStitchEndpoints(t *testing.T) {
+	// Each crop keeps one endpoint and loses the other.
+	top := testingMaze().Crop(Rect{Rows: 2, Cols: 4})
+	bottom := testingMaze().Crop(Rect{Row: 2, Rows: 2, Cols: 4})
+	if _, err := Stitch([][]*Maze{{top}, {bottom}}, nil); err == nil {
+		t.Error("expected error for start outside of tile")
+	}
+	if _, err := Stitch([][]*Maze{{bottom}, {bottom}}, nil); err == nil {
+		t.Error("expected error for end outside of tile")
+	}
+	if _, err := Stitch([][]*Maze{{bottom}, {top}}, nil); err != nil {
+		t.Error(err)
+	}
+
+	// The start and end are walls in this tile.
+	walled := testingMaze()
+	walled.Start, walled.End = Position{0, 1}, Position{3, 0}
+	maze, err := Stitch([][]*Maze{{walled, walled}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maze.Wall(maze.Start) || maze.Wall(maze.End) {
+		t.Error("start and end should be open")
+	}
+	if Solve(maze) == nil {
+		t.Errorf("maze should be solvable: %#v", maze.String())
+	}
+}
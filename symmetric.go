@@ -0,0 +1,118 @@
+package mazenv
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// SymmetricGenerator wraps a Generator to produce mazes
+// which look the same after a reflection or rotation.
+//
+// The maze is created by mirroring the half of a maze
+// from the wrapped Generator with more spaces onto the
+// other half.
+// If this disconnects the maze, walls are opened in
+// mirrored pairs until every space is connected.
+//
+// The end is placed at the mirror image of the start, so
+// the path from the start to the end is the mirror image
+// of the path from the end to the start.
+type SymmetricGenerator struct {
+	Generator Generator
+
+	// Symmetry is the kind of symmetry to produce.
+	// It may be "horizontal" (mirrored left to right),
+	// "vertical" (mirrored top to bottom), or
+	// "rotational" (unchanged by a half turn).
+	//
+	// If empty, a default of "horizontal" is used.
+	Symmetry string
+}
+
+// Mirror returns the position which corresponds to p on
+// the other side of a maze.
+func (s *SymmetricGenerator) Mirror(p Position, rows, cols int) Position {
+	switch s.Symmetry {
+	case "vertical":
+		return Position{Row: rows - 1 - p.Row, Col: p.Col}
+	case "rotational":
+		return Position{Row: rows - 1 - p.Row, Col: cols - 1 - p.Col}
+	default:
+		return Position{Row: p.Row, Col: cols - 1 - p.Col}
+	}
+}
+
+// Generate generates a symmetric maze.
+func (s *SymmetricGenerator) Generate(rows, cols int) (*Maze, error) {
+	return s.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+// The wrapped Generator only uses rng if it implements
+// RandGenerator.
+//
+// If rng is nil, the global source from math/rand is used.
+func (s *SymmetricGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*Maze, error) {
+	rng = randOrGlobal(rng)
+	switch s.Symmetry {
+	case "", "horizontal", "vertical", "rotational":
+	default:
+		return nil, errors.New("generate: unknown symmetry: " + s.Symmetry)
+	}
+	maze, err := generateRand(s.Generator, rng, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	mirror := func(p Position) Position {
+		return s.Mirror(p, rows, cols)
+	}
+
+	// Copy the half with more spaces onto the other half,
+	// since some generators leave large areas empty.
+	var firstSpaces, secondSpaces int
+	for _, p := range maze.Positions() {
+		if maze.Wall(p) {
+			continue
+		}
+		if i, j := maze.CellIndex(p), maze.CellIndex(mirror(p)); i < j {
+			firstSpaces++
+		} else if i > j {
+			secondSpaces++
+		}
+	}
+	for _, p := range maze.Positions() {
+		i, j := maze.CellIndex(p), maze.CellIndex(mirror(p))
+		if (i > j) == (firstSpaces >= secondSpaces) && i != j {
+			maze.Walls[i] = maze.Walls[j]
+		}
+	}
+
+	for {
+		regions := spaceRegions(maze)
+		if len(regions) < 2 {
+			break
+		}
+		oldWalls := append([]bool{}, maze.Walls...)
+		connectRegion(maze, regions[0], nil)
+		for i, wall := range oldWalls {
+			if wall && !maze.Walls[i] {
+				p := Position{Row: i / cols, Col: i % cols}
+				maze.Walls[maze.CellIndex(mirror(p))] = false
+			}
+		}
+	}
+
+	var options []Position
+	for _, p := range shuffledSpaces(rng, maze) {
+		if mirror(p) != p {
+			options = append(options, p)
+		}
+	}
+	if len(options) == 0 {
+		return nil, errors.New("generate: not enough spaces")
+	}
+	maze.Start = options[0]
+	maze.End = mirror(maze.Start)
+	return maze, nil
+}
@@ -0,0 +1,35 @@
+package mazenv
+
+import "testing"
+
+func TestSymmetricGenerator(t *testing.T) {
+	for _, symmetry := range []string{"horizontal", "vertical", "rotational"} {
+		for name, gen := range testGenerators() {
+			t.Run(symmetry+"/"+name, func(t *testing.T) {
+				sym := &SymmetricGenerator{Generator: gen, Symmetry: symmetry}
+				for _, size := range [][2]int{{21, 21}, {15, 23}} {
+					rows, cols := size[0], size[1]
+					m, err := sym.Generate(rows, cols)
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, p := range m.Positions() {
+						if m.Wall(p) != m.Wall(sym.Mirror(p, rows, cols)) {
+							t.Fatalf("asymmetric maze: %#v", m.String())
+						}
+					}
+					if m.End != sym.Mirror(m.Start, rows, cols) || m.Start == m.End {
+						t.Errorf("bad start %v and end %v", m.Start, m.End)
+					}
+					if n := len(spaceRegions(m)); n != 1 {
+						t.Errorf("expected 1 region but got %d", n)
+					}
+				}
+			})
+		}
+	}
+	if _, err := (&SymmetricGenerator{Generator: &PrimGenerator{},
+		Symmetry: "diagonal"}).Generate(11, 11); err == nil {
+		t.Error("expected error for unknown symmetry")
+	}
+}
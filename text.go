@@ -0,0 +1,172 @@
+package mazenv
+
+import "strings"
+
+// ANSI escape codes used by Maze.Text.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiGreen  = "\x1b[1;32m"
+	ansiRed    = "\x1b[1;31m"
+	ansiYellow = "\x1b[1;33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// TextOptions controls how Maze.Text draws a maze.
+type TextOptions struct {
+	// Block, if true, draws walls as '█' and spaces as '░'
+	// rather than 'w' and '.'.
+	// ParseMaze can read this style back.
+	Block bool
+
+	// Color, if true, highlights the start, end, agent,
+	// and path with ANSI escape codes.
+	Color bool
+
+	// Agent, if non-nil, is the position of an agent,
+	// which is drawn as '@'.
+	Agent *Position
+
+	// Path is a list of positions, such as the result of
+	// Solve, which are drawn as '*' (or '•' in the block
+	// style).
+	// The start and end are drawn as usual.
+	Path []Position
+}
+
+// Text produces a text representation of the grid like
+// String, but with extra styles and overlays.
+//
+// The options may be nil, in which case the result is
+// equivalent to String.
+func (m *Maze) Text(opts *TextOptions) string {
+	if opts == nil {
+		opts = &TextOptions{}
+	}
+	wallCh, spaceCh, pathCh := "w", ".", "*"
+	if opts.Block {
+		wallCh, spaceCh, pathCh = "█", "░", "•"
+	}
+	colored := func(s, code string) string {
+		if opts.Color {
+			return code + s + ansiReset
+		}
+		return s
+	}
+	onPath := map[Position]bool{}
+	for _, p := range opts.Path {
+		onPath[p] = true
+	}
+
+	rows := make([]string, m.Rows)
+	for row := 0; row < m.Rows; row++ {
+		var line strings.Builder
+		for col := 0; col < m.Cols; col++ {
+			pos := Position{Row: row, Col: col}
+			switch {
+			case opts.Agent != nil && pos == *opts.Agent:
+				line.WriteString(colored("@", ansiYellow))
+			case pos == m.Start:
+				line.WriteString(colored("A", ansiGreen))
+			case pos == m.End:
+				line.WriteString(colored("x", ansiRed))
+			case m.Wall(pos):
+				line.WriteString(wallCh)
+			case onPath[pos]:
+				line.WriteString(colored(pathCh, ansiCyan))
+			default:
+				line.WriteString(spaceCh)
+			}
+		}
+		rows[row] = line.String()
+	}
+	return strings.Join(rows, "\n")
+}
+
+// RenderWalls produces a Unicode representation of the
+// grid which draws the walls between cells as thin lines.
+//
+// Each cell is three characters wide, and walls take up
+// their own rows and columns, so the result has 2*Rows+1
+// lines of 4*Cols+1 characters.
+// Cells with tunnels show the passage on top as '┃' or
+// '━━━'.
+// The start and end are drawn as 'A' and 'x'.
+func (w *WeaveMaze) RenderWalls() string {
+	// vertWall checks for a wall on the west side of a
+	// cell, and horizWall on the north side.
+	vertWall := func(row, col int) bool {
+		if row < 0 || row >= w.Rows {
+			return false
+		}
+		return !w.Open(Position{row, col}, WeaveWest) &&
+			!w.Open(Position{row, col - 1}, WeaveEast)
+	}
+	horizWall := func(row, col int) bool {
+		if col < 0 || col >= w.Cols {
+			return false
+		}
+		return !w.Open(Position{row, col}, WeaveNorth) &&
+			!w.Open(Position{row - 1, col}, WeaveSouth)
+	}
+
+	var lines []string
+	for row := 0; row <= w.Rows; row++ {
+		var line strings.Builder
+		for col := 0; col <= w.Cols; col++ {
+			var mask int
+			for i, wall := range []bool{
+				vertWall(row-1, col),
+				horizWall(row, col),
+				vertWall(row, col),
+				horizWall(row, col-1),
+			} {
+				if wall {
+					mask |= 1 << uint(i)
+				}
+			}
+			if mask == 0 {
+				line.WriteRune(' ')
+			} else {
+				line.WriteRune(weaveRunes[mask])
+			}
+			if col < w.Cols {
+				if horizWall(row, col) {
+					line.WriteString("───")
+				} else {
+					line.WriteString("   ")
+				}
+			}
+		}
+		lines = append(lines, line.String())
+		if row == w.Rows {
+			break
+		}
+
+		line.Reset()
+		for col := 0; col <= w.Cols; col++ {
+			if vertWall(row, col) {
+				line.WriteString("│")
+			} else {
+				line.WriteString(" ")
+			}
+			if col == w.Cols {
+				break
+			}
+			pos := Position{row, col}
+			switch {
+			case pos == w.Start:
+				line.WriteString(" A ")
+			case pos == w.End:
+				line.WriteString(" x ")
+			case w.Tunnel(pos) && w.Open(pos, WeaveNorth):
+				line.WriteString(" ┃ ")
+			case w.Tunnel(pos):
+				line.WriteString("━━━")
+			default:
+				line.WriteString("   ")
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
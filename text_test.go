@@ -0,0 +1,54 @@
+package mazenv
+
+import "testing"
+
+func TestMazeText(t *testing.T) {
+	maze := testingMaze()
+	if actual := maze.Text(nil); actual != maze.String() {
+		t.Errorf("expected %#v but got %#v", maze.String(), actual)
+	}
+
+	block := maze.Text(&TextOptions{Block: true})
+	expected := "░██░\n█░█x\n░A██\n█░░░"
+	if block != expected {
+		t.Errorf("expected %#v but got %#v", expected, block)
+	}
+	parsed, err := ParseMaze(block)
+	if err != nil {
+		t.Fatal(err)
+	} else if parsed.String() != maze.String() {
+		t.Errorf("expected %#v but got %#v", maze.String(), parsed.String())
+	}
+
+	agent := Position{1, 1}
+	actual := maze.Text(&TextOptions{Agent: &agent, Path: Solve(maze)})
+	expected = ".ww.\nw@wx\n.Aww\nw..."
+	if actual != expected {
+		t.Errorf("expected %#v but got %#v", expected, actual)
+	}
+
+	maze, err = ParseMaze("A.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual = maze.Text(&TextOptions{Color: true, Path: Solve(maze)})
+	expected = ansiGreen + "A" + ansiReset + ansiCyan + "*" + ansiReset +
+		ansiRed + "x" + ansiReset
+	if actual != expected {
+		t.Errorf("expected %#v but got %#v", expected, actual)
+	}
+}
+
+func TestWeaveMazeRenderWalls(t *testing.T) {
+	actual := testingWeaveMaze().RenderWalls()
+	expected := "┌───┬───┬───┐\n" +
+		"│   │   │   │\n" +
+		"├───┘   └───┤\n" +
+		"│ A   ┃   x │\n" +
+		"├───┐   ┌───┤\n" +
+		"│   │   │   │\n" +
+		"└───┴───┴───┘"
+	if actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,100 @@
+package mazenv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/unixpickle/essentials"
+)
+
+// Rotate90 creates a new maze by rotating the grid 90
+// degrees clockwise.
+func (m *Maze) Rotate90() *Maze {
+	return m.transformed(m.Cols, m.Rows, func(p Position) Position {
+		return Position{Row: p.Col, Col: m.Rows - 1 - p.Row}
+	})
+}
+
+// FlipHorizontal creates a new maze by mirroring the grid
+// from left to right.
+func (m *Maze) FlipHorizontal() *Maze {
+	return m.transformed(m.Rows, m.Cols, func(p Position) Position {
+		return Position{Row: p.Row, Col: m.Cols - 1 - p.Col}
+	})
+}
+
+// FlipVertical creates a new maze by mirroring the grid
+// from top to bottom.
+func (m *Maze) FlipVertical() *Maze {
+	return m.transformed(m.Rows, m.Cols, func(p Position) Position {
+		return Position{Row: m.Rows - 1 - p.Row, Col: p.Col}
+	})
+}
+
+// Transpose creates a new maze by swapping the rows and
+// columns of the grid.
+func (m *Maze) Transpose() *Maze {
+	return m.transformed(m.Cols, m.Rows, func(p Position) Position {
+		return Position{Row: p.Col, Col: p.Row}
+	})
+}
+
+// transformed creates a maze by moving every cell of m to
+// a new position in a grid of the given size.
+func (m *Maze) transformed(rows, cols int, f func(p Position) Position) *Maze {
+	res := &Maze{
+		Rows:  rows,
+		Cols:  cols,
+		Start: f(m.Start),
+		End:   f(m.End),
+		Walls: make([]bool, rows*cols),
+	}
+	for _, p := range m.Positions() {
+		res.Walls[res.CellIndex(f(p))] = m.Wall(p)
+	}
+	return res
+}
+
+// Symmetries returns the eight mazes obtained by rotating
+// and reflecting the maze, starting with a copy of the
+// maze itself.
+func (m *Maze) Symmetries() []*Maze {
+	var res []*Maze
+	for _, cur := range []*Maze{m.transformed(m.Rows, m.Cols, func(p Position) Position {
+		return p
+	}), m.Transpose()} {
+		for i := 0; i < 4; i++ {
+			res = append(res, cur)
+			cur = cur.Rotate90()
+		}
+	}
+	return res
+}
+
+// CanonicalHash computes a SHA-256 hash of the maze which
+// is the same for all of its Symmetries.
+//
+// Two mazes have the same hash if and only if (barring
+// hash collisions) one can be rotated or reflected into
+// the other, including the start and end.
+//
+// An error is returned for mazes that cannot be encoded
+// with MarshalBinary.
+func (m *Maze) CanonicalHash() (hash [sha256.Size]byte, err error) {
+	defer essentials.AddCtxTo("canonical hash", &err)
+	if m.Rows < 0 || m.Cols < 0 || len(m.Walls) != m.Rows*m.Cols {
+		return hash, errors.New("dimensions do not match walls")
+	}
+	for i, sym := range m.Symmetries() {
+		data, err := sym.MarshalBinary()
+		if err != nil {
+			return hash, err
+		}
+		symHash := sha256.Sum256(data)
+		if i == 0 || bytes.Compare(symHash[:], hash[:]) < 0 {
+			hash = symHash
+		}
+	}
+	return hash, nil
+}
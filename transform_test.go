@@ -0,0 +1,79 @@
+package mazenv
+
+import "testing"
+
+func TestMazeTransforms(t *testing.T) {
+	maze := testingMaze()
+	cases := map[string]struct {
+		Actual   *Maze
+		Expected string
+	}{
+		"Rotate90":       {maze.Rotate90(), "w.w.\n.A.w\n.www\n.wx."},
+		"FlipHorizontal": {maze.FlipHorizontal(), ".ww.\nxw.w\nwwA.\n...w"},
+		"FlipVertical":   {maze.FlipVertical(), "w...\n.Aww\nw.wx\n.ww."},
+		"Transpose":      {maze.Transpose(), ".w.w\nw.A.\nwww.\n.xw."},
+	}
+	for name, c := range cases {
+		if actual := c.Actual.String(); actual != c.Expected {
+			t.Errorf("%s: expected %#v but got %#v", name, c.Expected, actual)
+		}
+	}
+
+	maze, err := ParseMaze("A.w\n.wx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := maze.Rotate90()
+	if rotated.Rows != 3 || rotated.Cols != 2 || rotated.String() != ".A\nw.\nxw" {
+		t.Errorf("unexpected rotation: %#v", rotated.String())
+	}
+	for i := 0; i < 3; i++ {
+		rotated = rotated.Rotate90()
+	}
+	if rotated.String() != maze.String() {
+		t.Errorf("four rotations gave %#v", rotated.String())
+	}
+}
+
+func TestCanonicalHash(t *testing.T) {
+	maze, err := GenerateSeed(&PrimGenerator{}, 1, 9, 13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := func(m *Maze) [32]byte {
+		res, err := m.CanonicalHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	expected := hash(maze)
+	syms := maze.Symmetries()
+	if len(syms) != 8 {
+		t.Fatalf("expected 8 symmetries but got %d", len(syms))
+	}
+	for i, sym := range syms {
+		if hash(sym) != expected {
+			t.Errorf("symmetry %d has a different hash", i)
+		}
+	}
+	if hash(maze.FlipVertical().Transpose()) != expected {
+		t.Error("composed transform has a different hash")
+	}
+
+	other := maze.Rotate90()
+	other.Start, other.End = other.End, other.Start
+	if hash(other) == expected {
+		t.Error("swapping start and end should change the hash")
+	}
+
+	invalid := []*Maze{
+		{Rows: 2, Cols: 2, Walls: make([]bool, 3)},
+		{Rows: 2, Cols: 2, Start: Position{-1, 0}, Walls: make([]bool, 4)},
+	}
+	for i, m := range invalid {
+		if _, err := m.CanonicalHash(); err == nil {
+			t.Errorf("maze %d: expected an error", i)
+		}
+	}
+}
@@ -29,17 +29,140 @@ func neighboringSpaces(m *Maze, p Position) []Position {
 	return res
 }
 
-func oneHotGrid(m *Maze, curPos Position, startRow, startCol, rows, cols int) []float64 {
+// distances computes the number of steps from start to
+// every cell in the maze, indexed by CellIndex.
+//
+// Unreachable cells have a distance of -1.
+func distances(m *Maze, start Position) []int {
+	res := make([]int, len(m.Walls))
+	for i := range res {
+		res[i] = -1
+	}
+	res[m.CellIndex(start)] = 0
+	queue := []Position{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, n := range neighboringSpaces(m, p) {
+			if idx := m.CellIndex(n); res[idx] == -1 {
+				res[idx] = res[m.CellIndex(p)] + 1
+				queue = append(queue, n)
+			}
+		}
+	}
+	return res
+}
+
+// spaceRegions finds the connected regions of spaces in
+// the maze.
+func spaceRegions(m *Maze) [][]Position {
+	var res [][]Position
+	visited := make([]bool, len(m.Walls))
+	for _, p := range m.Positions() {
+		if m.Wall(p) || visited[m.CellIndex(p)] {
+			continue
+		}
+		visited[m.CellIndex(p)] = true
+		region := []Position{p}
+		for i := 0; i < len(region); i++ {
+			for _, n := range neighboringSpaces(m, region[i]) {
+				if !visited[m.CellIndex(n)] {
+					visited[m.CellIndex(n)] = true
+					region = append(region, n)
+				}
+			}
+		}
+		res = append(res, region)
+	}
+	return res
+}
+
+// connectRegions opens walls until all of the spaces in
+// the maze are connected.
+//
+// Regions are joined along the shortest possible tunnels.
+// If allowed is non-nil, only walls for which it returns
+// true may be opened, so some regions may be left
+// disconnected.
+func connectRegions(m *Maze, allowed func(p Position) bool) {
+	// Regions which cannot reach any other region are
+	// identified by their first position.
+	isolated := map[Position]bool{}
+	for {
+		var regions [][]Position
+		for _, region := range spaceRegions(m) {
+			if !isolated[region[0]] {
+				regions = append(regions, region)
+			}
+		}
+		if len(regions) < 2 {
+			return
+		}
+		if !connectRegion(m, regions[0], allowed) {
+			isolated[regions[0][0]] = true
+		}
+	}
+}
+
+// connectRegion opens a shortest tunnel from a region to
+// any other space.
+// It returns false if no tunnel is possible.
+func connectRegion(m *Maze, region []Position, allowed func(p Position) bool) bool {
+	parents := map[Position]Position{}
+	queue := append([]Position{}, region...)
+	for _, p := range queue {
+		parents[p] = p
+	}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, n := range neighbors(m, p) {
+			if _, ok := parents[n]; ok {
+				continue
+			}
+			parents[n] = p
+			if !m.Wall(n) {
+				for cur := p; m.Wall(cur); cur = parents[cur] {
+					m.Walls[m.CellIndex(cur)] = false
+				}
+				return true
+			}
+			if allowed == nil || allowed(n) {
+				queue = append(queue, n)
+			}
+		}
+	}
+	return false
+}
+
+// keepLargestRegion fills every region of spaces except
+// for the largest one with walls.
+func keepLargestRegion(m *Maze) {
+	regions := spaceRegions(m)
+	for i, region := range regions {
+		if len(region) > len(regions[0]) {
+			regions[0], regions[i] = regions[i], regions[0]
+		}
+	}
+	for i := 1; i < len(regions); i++ {
+		for _, p := range regions[i] {
+			m.Walls[m.CellIndex(p)] = true
+		}
+	}
+}
+
+func oneHotGrid(g Grid, curPos Position, startRow, startCol, rows, cols int) []float64 {
+	start, end := g.Endpoints()
 	var res []float64
 	for row := startRow; row < startRow+rows; row++ {
 		for col := startCol; col < startCol+cols; col++ {
 			pos := Position{row, col}
 			cellType := CellEmpty
-			if m.Start == pos {
+			if start == pos {
 				cellType = CellStart
-			} else if m.End == pos {
+			} else if end == pos {
 				cellType = CellEnd
-			} else if m.Wall(pos) {
+			} else if g.Wall(pos) {
 				cellType = CellWall
 			}
 			if pos == curPos {
@@ -59,7 +182,14 @@ func oneHot(num, val int) []float64 {
 	return res
 }
 
-func shuffledSpaces(m *Maze, exclude ...Position) []Position {
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func shuffledSpaces(rng *rand.Rand, m *Maze, exclude ...Position) []Position {
 	var options []Position
 
 PosLoop:
@@ -75,9 +205,91 @@ PosLoop:
 	}
 
 	for i := 0; i < len(options); i++ {
-		j := i + rand.Intn(len(options)-i)
+		j := i + rng.Intn(len(options)-i)
 		options[i], options[j] = options[j], options[i]
 	}
 
 	return options
 }
+
+// disjointSet is a union-find structure over the integers
+// [0, len(d)).
+type disjointSet []int
+
+func newDisjointSet(n int) disjointSet {
+	res := make(disjointSet, n)
+	for i := range res {
+		res[i] = i
+	}
+	return res
+}
+
+// Find returns the representative of i's set.
+func (d disjointSet) Find(i int) int {
+	for d[i] != i {
+		d[i] = d[d[i]]
+		i = d[i]
+	}
+	return i
+}
+
+// Union merges the sets containing i and j.
+//
+// It returns false if i and j were already in the same
+// set.
+func (d disjointSet) Union(i, j int) bool {
+	i, j = d.Find(i), d.Find(j)
+	if i == j {
+		return false
+	}
+	d[j] = i
+	return true
+}
+
+// globalSource is a rand.Source64 which uses the global
+// source from math/rand.
+type globalSource struct{}
+
+func (g globalSource) Int63() int64 {
+	return rand.Int63()
+}
+
+func (g globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+func (g globalSource) Seed(seed int64) {
+	rand.Seed(seed)
+}
+
+// randOrGlobal returns rng, or a generator backed by the
+// global source if rng is nil.
+func randOrGlobal(rng *rand.Rand) *rand.Rand {
+	if rng != nil {
+		return rng
+	}
+	return rand.New(globalSource{})
+}
+
+// manhattanDistance computes the L1 distance between two
+// positions.
+func manhattanDistance(p1, p2 Position) int {
+	var res int
+	for _, d := range []int{p1.Row - p2.Row, p1.Col - p2.Col} {
+		if d < 0 {
+			res -= d
+		} else {
+			res += d
+		}
+	}
+	return res
+}
+
+func sign(x int) int {
+	if x < 0 {
+		return -1
+	} else if x > 0 {
+		return 1
+	}
+	return 0
+}
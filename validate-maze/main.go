@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/mazenv"
+)
+
+func main() {
+	var mazesPath string
+	var opts mazenv.ValidateOptions
+	flag.StringVar(&mazesPath, "in", "", "file containing mazes (instead of stdin)")
+	flag.BoolVar(&opts.Solvable, "solvable", false, "check that every maze is solvable")
+	flag.BoolVar(&opts.Connected, "connected", false,
+		"check that every space is reachable from the start")
+	flag.Parse()
+
+	var data []byte
+	var err error
+	if mazesPath == "" {
+		fmt.Fprintln(os.Stderr, "reading from standard input...")
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(mazesPath)
+	}
+	if err != nil {
+		essentials.Die(err)
+	}
+
+	var numMazes, numProblems int
+	report := func(mazeIdx, line int, msg string) {
+		numProblems++
+		fmt.Printf("maze %d (line %d): %s\n", mazeIdx, line, msg)
+	}
+	for _, chunk := range splitMazes(string(data)) {
+		numMazes++
+		maze, err := mazenv.ParseMazeStrict(chunk.Text)
+		if err != nil {
+			for _, parseErr := range err.(mazenv.ParseErrors) {
+				if parseErr.Line == 0 {
+					report(numMazes, chunk.Line, parseErr.Msg)
+				} else {
+					report(numMazes, chunk.Line+parseErr.Line-1,
+						fmt.Sprintf("column %d: %s", parseErr.Col, parseErr.Msg))
+				}
+			}
+			continue
+		}
+		if err := maze.Validate(&opts); err != nil {
+			for _, problem := range err.(*mazenv.ValidationError).Problems {
+				report(numMazes, chunk.Line, problem)
+			}
+		}
+	}
+
+	if numProblems > 0 {
+		fmt.Fprintf(os.Stderr, "found %d problems in %d mazes\n", numProblems, numMazes)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "all %d mazes are valid\n", numMazes)
+}
+
+type mazeChunk struct {
+	// Line is the 1-based line number where the maze
+	// starts.
+	Line int
+	Text string
+}
+
+// splitMazes splits a file into mazes separated by blank
+// lines.
+func splitMazes(data string) []mazeChunk {
+	var res []mazeChunk
+	var cur *mazeChunk
+	for i, line := range strings.Split(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			res = append(res, mazeChunk{Line: i + 1})
+			cur = &res[len(res)-1]
+		}
+		cur.Text += line + "\n"
+	}
+	return res
+}
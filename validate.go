@@ -0,0 +1,211 @@
+package mazenv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unixpickle/essentials"
+)
+
+// ValidateOptions specifies optional checks for Validate.
+type ValidateOptions struct {
+	// Solvable, if true, checks that the end can be
+	// reached from the start.
+	Solvable bool
+
+	// Connected, if true, checks that every space can be
+	// reached from the start.
+	Connected bool
+}
+
+// ValidationError lists the problems found by Validate.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error returns a message with every problem.
+func (v *ValidationError) Error() string {
+	return "invalid maze: " + strings.Join(v.Problems, "; ")
+}
+
+// Validate checks that the maze is well-formed.
+//
+// The dimensions must match the number of walls, and the
+// start and end must be distinct spaces in the grid.
+// Other checks may be enabled with the options, which may
+// be nil.
+//
+// If there are any problems, a *ValidationError is
+// returned.
+func (m *Maze) Validate(opts *ValidateOptions) error {
+	if opts == nil {
+		opts = &ValidateOptions{}
+	}
+	var problems []string
+	if m.Rows < 0 || m.Cols < 0 {
+		problems = append(problems, fmt.Sprintf("negative dimensions %dx%d", m.Rows, m.Cols))
+	} else if len(m.Walls) != m.Rows*m.Cols {
+		problems = append(problems, fmt.Sprintf("%d walls for %dx%d grid", len(m.Walls),
+			m.Rows, m.Cols))
+	}
+	if len(problems) > 0 {
+		// The other checks would index out of bounds.
+		return &ValidationError{Problems: problems}
+	}
+
+	for _, pt := range []struct {
+		Name string
+		Pos  Position
+	}{{"start", m.Start}, {"end", m.End}} {
+		if !m.InBounds(pt.Pos) {
+			problems = append(problems, fmt.Sprintf("%s %v out of bounds", pt.Name, pt.Pos))
+		} else if m.Wall(pt.Pos) {
+			problems = append(problems, fmt.Sprintf("%s %v is a wall", pt.Name, pt.Pos))
+		}
+	}
+	if m.Start == m.End {
+		problems = append(problems, "start and end are the same")
+	}
+	if len(problems) == 0 {
+		dists := distances(m, m.Start)
+		if opts.Solvable && dists[m.CellIndex(m.End)] < 0 {
+			problems = append(problems, "end is unreachable")
+		}
+		if opts.Connected {
+			var unreachable int
+			for i, d := range dists {
+				if d < 0 && !m.Walls[i] {
+					unreachable++
+				}
+			}
+			if unreachable > 0 {
+				problems = append(problems, fmt.Sprintf("%d unreachable spaces", unreachable))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ParseError is a problem at a specific place in the
+// text of a maze.
+type ParseError struct {
+	// Line and Col are 1-based.
+	// Col counts characters, not bytes.
+	//
+	// If the problem does not have a specific place, such
+	// as a missing start, both are 0.
+	Line int
+	Col  int
+
+	Msg string
+}
+
+// Error returns a message with the location.
+func (p *ParseError) Error() string {
+	if p.Line == 0 {
+		return "parse maze: " + p.Msg
+	}
+	return fmt.Sprintf("parse maze: line %d, column %d: %s", p.Line, p.Col, p.Msg)
+}
+
+// ParseErrors is a list of every problem found by
+// ParseMazeStrict.
+type ParseErrors []*ParseError
+
+// Error returns a message with every problem.
+func (p ParseErrors) Error() string {
+	var msgs []string
+	for _, err := range p {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns every problem, so that errors.As can
+// find the first *ParseError.
+func (p ParseErrors) Unwrap() []error {
+	res := make([]error, len(p))
+	for i, err := range p {
+		res[i] = err
+	}
+	return res
+}
+
+// ParseMazeStrict is like ParseMaze, but it rejects
+// unknown characters and finds every problem in the text.
+//
+// Blank lines before and after the maze are ignored, and
+// lines may end with "\r\n".
+//
+// If there are any problems, the error is a ParseErrors.
+func ParseMazeStrict(s string) (*Maze, error) {
+	lines := strings.Split(strings.Replace(s, "\r\n", "\n", -1), "\n")
+	firstLine := 0
+	for firstLine < len(lines) && strings.TrimSpace(lines[firstLine]) == "" {
+		firstLine++
+	}
+	for len(lines) > firstLine && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if firstLine == len(lines) {
+		return nil, ParseErrors{{Msg: "empty maze"}}
+	}
+
+	var errs ParseErrors
+	var seenStart, seenEnd bool
+	maze := &Maze{Cols: len([]rune(lines[firstLine]))}
+	for i := firstLine; i < len(lines); i++ {
+		line := []rune(lines[i])
+		if len(line) != maze.Cols {
+			errs = append(errs, &ParseError{
+				Line: i + 1,
+				Col:  essentials.MinInt(len(line), maze.Cols) + 1,
+				Msg:  fmt.Sprintf("expected %d columns but got %d", maze.Cols, len(line)),
+			})
+		}
+		row := make([]bool, maze.Cols)
+		for col, ch := range line {
+			pos := Position{Row: maze.Rows, Col: col}
+			var wall bool
+			switch ch {
+			case '.', '░':
+			case 'w', '█':
+				wall = true
+			case 'A':
+				if seenStart {
+					errs = append(errs, &ParseError{Line: i + 1, Col: col + 1,
+						Msg: "multiple starts"})
+				}
+				maze.Start, seenStart = pos, true
+			case 'x':
+				if seenEnd {
+					errs = append(errs, &ParseError{Line: i + 1, Col: col + 1,
+						Msg: "multiple ends"})
+				}
+				maze.End, seenEnd = pos, true
+			default:
+				errs = append(errs, &ParseError{Line: i + 1, Col: col + 1,
+					Msg: fmt.Sprintf("unknown cell %q", ch)})
+			}
+			if col < maze.Cols {
+				row[col] = wall
+			}
+		}
+		maze.Walls = append(maze.Walls, row...)
+		maze.Rows++
+	}
+	if !seenStart {
+		errs = append(errs, &ParseError{Msg: "missing start"})
+	}
+	if !seenEnd {
+		errs = append(errs, &ParseError{Msg: "missing end"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return maze, nil
+}
@@ -0,0 +1,80 @@
+package mazenv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMazeValidate(t *testing.T) {
+	if err := testingMaze().Validate(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	maze, err := ParseMaze("A.w.\nwww.\n..wx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := maze.Validate(nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = maze.Validate(&ValidateOptions{Solvable: true, Connected: true})
+	expected := []string{"end is unreachable", "5 unreachable spaces"}
+	if verr, ok := err.(*ValidationError); !ok {
+		t.Errorf("expected *ValidationError but got %v", err)
+	} else if !reflect.DeepEqual(verr.Problems, expected) {
+		t.Errorf("expected %v but got %v", expected, verr.Problems)
+	}
+
+	maze = testingMaze()
+	maze.Start = Position{0, 1}
+	maze.End = Position{4, 0}
+	err = maze.Validate(nil)
+	expected = []string{"start {0 1} is a wall", "end {4 0} out of bounds"}
+	if verr, ok := err.(*ValidationError); !ok {
+		t.Errorf("expected *ValidationError but got %v", err)
+	} else if !reflect.DeepEqual(verr.Problems, expected) {
+		t.Errorf("expected %v but got %v", expected, verr.Problems)
+	}
+
+	maze.Walls = maze.Walls[1:]
+	if err := maze.Validate(nil); err == nil {
+		t.Error("expected error for mismatched walls")
+	}
+}
+
+func TestParseMazeStrict(t *testing.T) {
+	expected := testingMaze()
+	actual, err := ParseMazeStrict("\n" + expected.String() + "\r\n\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+
+	_, err = ParseMazeStrict("\nA.?\n..\nAwwx\nx...")
+	expectedErrs := ParseErrors{
+		{Line: 2, Col: 3, Msg: `unknown cell '?'`},
+		{Line: 3, Col: 3, Msg: "expected 3 columns but got 2"},
+		{Line: 4, Col: 4, Msg: "expected 3 columns but got 4"},
+		{Line: 4, Col: 1, Msg: "multiple starts"},
+		{Line: 5, Col: 4, Msg: "expected 3 columns but got 4"},
+		{Line: 5, Col: 1, Msg: "multiple ends"},
+	}
+	if !reflect.DeepEqual(err, expectedErrs) {
+		t.Errorf("expected %v but got %v", expectedErrs, err)
+	}
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Error("expected errors.As to find a *ParseError")
+	} else if !reflect.DeepEqual(pe, expectedErrs[0]) {
+		t.Errorf("expected %v but got %v", expectedErrs[0], pe)
+	}
+
+	_, err = ParseMazeStrict("...")
+	expectedErrs = ParseErrors{{Msg: "missing start"}, {Msg: "missing end"}}
+	if !reflect.DeepEqual(err, expectedErrs) {
+		t.Errorf("expected %v but got %v", expectedErrs, err)
+	}
+}
@@ -0,0 +1,228 @@
+package mazenv
+
+import "strings"
+
+// Directions in a WeaveMaze.
+const (
+	WeaveNorth = iota
+	WeaveEast
+	WeaveSouth
+	WeaveWest
+)
+
+var weaveOffsets = [4]Position{
+	WeaveNorth: {Row: -1},
+	WeaveEast:  {Col: 1},
+	WeaveSouth: {Row: 1},
+	WeaveWest:  {Col: -1},
+}
+
+// weaveRunes maps passage bitmasks to box-drawing
+// characters.
+var weaveRunes = [16]rune{
+	'·', '╵', '╶', '└', '╷', '│', '┌', '├',
+	'╴', '┘', '─', '┴', '┐', '┤', '┬', '┼',
+}
+
+// WeaveState is a place in a WeaveMaze.
+type WeaveState struct {
+	Position
+
+	// Under is true when passing through a tunnel
+	// underneath a cell.
+	Under bool
+}
+
+// WeaveMaze defines a maze on a grid of cells where
+// passages may cross over and under each other.
+//
+// Walls are the edges between cells without a passage.
+// A cell with a tunnel has a straight passage on the
+// surface, as well as a passage underneath the surface
+// which runs perpendicular to it.
+// The tunnel connects the two neighbors of the cell which
+// are not connected by the surface passage.
+type WeaveMaze struct {
+	Rows int
+	Cols int
+
+	Start Position
+	End   Position
+
+	// Passages is a row-major list of bitmasks specifying
+	// the passages out of each cell.
+	// Bit d is set if there is a passage in direction d,
+	// such as WeaveNorth.
+	//
+	// Surface passages are set on both cells they join.
+	// A tunnel is set on the cells at either end of it,
+	// but not on the cell that it passes under.
+	Passages []uint8
+
+	// Tunnels is a row-major list specifying which cells
+	// have a tunnel running underneath them.
+	Tunnels []bool
+}
+
+// NewWeaveMaze creates a maze with no passages.
+func NewWeaveMaze(rows, cols int) *WeaveMaze {
+	return &WeaveMaze{
+		Rows:     rows,
+		Cols:     cols,
+		Passages: make([]uint8, rows*cols),
+		Tunnels:  make([]bool, rows*cols),
+	}
+}
+
+// InBounds checks if the position is within the grid.
+func (w *WeaveMaze) InBounds(pos Position) bool {
+	return pos.Row >= 0 && pos.Row < w.Rows &&
+		pos.Col >= 0 && pos.Col < w.Cols
+}
+
+// Positions returns all valid positions in the grid in
+// the same order as w.Passages.
+func (w *WeaveMaze) Positions() []Position {
+	var res []Position
+	for row := 0; row < w.Rows; row++ {
+		for col := 0; col < w.Cols; col++ {
+			res = append(res, Position{row, col})
+		}
+	}
+	return res
+}
+
+// CellIndex gets the index for the cell.
+//
+// The position must be within bounds.
+func (w *WeaveMaze) CellIndex(pos Position) int {
+	if !w.InBounds(pos) {
+		panic("out of bounds")
+	}
+	return pos.Row*w.Cols + pos.Col
+}
+
+// Open checks if there is a surface passage out of the
+// cell in the direction.
+//
+// If the cell is out of bounds, false is returned.
+func (w *WeaveMaze) Open(pos Position, dir int) bool {
+	if !w.InBounds(pos) {
+		return false
+	}
+	return w.Passages[w.CellIndex(pos)]&(1<<uint(dir)) != 0
+}
+
+// Tunnel checks if a tunnel passes underneath the cell.
+//
+// If the cell is out of bounds, false is returned.
+func (w *WeaveMaze) Tunnel(pos Position) bool {
+	return w.InBounds(pos) && w.Tunnels[w.CellIndex(pos)]
+}
+
+// Move finds the state reached by moving from a state in
+// the direction.
+//
+// While in a tunnel, it is only possible to move along
+// the tunnel.
+// Entering a cell along its surface passage leads onto
+// the surface, while entering it along its tunnel leads
+// underneath it.
+//
+// If the move is blocked or the direction is invalid,
+// false is returned.
+func (w *WeaveMaze) Move(s WeaveState, dir int) (WeaveState, bool) {
+	if dir < 0 || dir >= 4 {
+		return s, false
+	} else if s.Under {
+		if !w.Tunnel(s.Position) || w.Open(s.Position, dir) ||
+			w.Open(s.Position, (dir+2)%4) {
+			return s, false
+		}
+	} else if !w.Open(s.Position, dir) {
+		return s, false
+	}
+	next := WeaveState{Position: addOffset(s.Position, weaveOffsets[dir])}
+	if w.Open(next.Position, (dir+2)%4) {
+		return next, true
+	} else if w.Tunnel(next.Position) {
+		next.Under = true
+		return next, true
+	}
+	return s, false
+}
+
+// Render produces a Unicode representation of the grid,
+// with one character per cell.
+//
+// Cells are drawn as box-drawing characters which show
+// their surface passages.
+// Cells with tunnels are drawn as '╂' or '┿', where the
+// heavy line is the passage on top.
+// The start and end are drawn as 'A' and 'x'.
+func (w *WeaveMaze) Render() string {
+	rows := make([]string, w.Rows)
+	for row := 0; row < w.Rows; row++ {
+		var line []rune
+		for col := 0; col < w.Cols; col++ {
+			pos := Position{row, col}
+			passages := w.Passages[w.CellIndex(pos)]
+			ch := weaveRunes[passages]
+			if pos == w.Start {
+				ch = 'A'
+			} else if pos == w.End {
+				ch = 'x'
+			} else if w.Tunnel(pos) {
+				if passages&(1<<WeaveNorth) != 0 {
+					ch = '╂'
+				} else {
+					ch = '┿'
+				}
+			}
+			line = append(line, ch)
+		}
+		rows[row] = string(line)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// SolveWeave finds an optimal solution to the maze.
+//
+// The solution is represented as a list of states that
+// comprise the solution, including the start and end.
+//
+// If no solution is found, nil is returned.
+func SolveWeave(w *WeaveMaze) []WeaveState {
+	start, end := WeaveState{Position: w.Start}, WeaveState{Position: w.End}
+	parents := map[WeaveState]WeaveState{start: start}
+	queue := []WeaveState{start}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		if s == end {
+			var res []WeaveState
+			for s != start {
+				res = append(res, s)
+				s = parents[s]
+			}
+			res = append(res, start)
+			for i := 0; i < len(res)/2; i++ {
+				res[i], res[len(res)-1-i] = res[len(res)-1-i], res[i]
+			}
+			return res
+		}
+		for dir := 0; dir < 4; dir++ {
+			if next, ok := w.Move(s, dir); ok {
+				if _, ok := parents[next]; !ok {
+					parents[next] = s
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func addOffset(p, offset Position) Position {
+	return Position{Row: p.Row + offset.Row, Col: p.Col + offset.Col}
+}
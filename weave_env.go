@@ -0,0 +1,104 @@
+package mazenv
+
+import (
+	"errors"
+
+	"github.com/unixpickle/anyrl"
+)
+
+// WeaveEnv is a generic environment for weave mazes.
+//
+// Actions are one-hot vectors with five possibilities,
+// just like for Env.
+type WeaveEnv interface {
+	anyrl.Env
+
+	// Maze returns the environment's map.
+	Maze() *WeaveMaze
+
+	// State returns the player's current position and
+	// whether it is underneath a crossing.
+	State() WeaveState
+}
+
+// rawWeaveEnv is a barebones environment for a weave maze.
+type rawWeaveEnv struct {
+	maze  *WeaveMaze
+	state WeaveState
+}
+
+// NewWeaveEnv creates a WeaveEnv for the maze.
+//
+// Observations are row-major representations of the
+// maze grid, followed by a boolean indicating if the
+// player is underneath a crossing.
+// Each cell is represented as a boolean (is current
+// position), followed by four booleans indicating surface
+// passages in each direction (north, east, south, west),
+// followed by three booleans (has tunnel, is start, is
+// end).
+//
+// Rewards are -1 until the maze is solved, at which point
+// the episode ends and the reward is 0.
+func NewWeaveEnv(maze *WeaveMaze) WeaveEnv {
+	return &rawWeaveEnv{maze: maze}
+}
+
+// Maze returns the maze.
+func (r *rawWeaveEnv) Maze() *WeaveMaze {
+	return r.maze
+}
+
+// State returns the current state.
+func (r *rawWeaveEnv) State() WeaveState {
+	return r.state
+}
+
+// Reset resets the player's position to the start.
+func (r *rawWeaveEnv) Reset() (obs []float64, err error) {
+	r.state = WeaveState{Position: r.maze.Start}
+	return r.observation(), nil
+}
+
+// Step takes a step in the environment.
+func (r *rawWeaveEnv) Step(action []float64) (obs []float64, reward float64,
+	done bool, err error) {
+	end := WeaveState{Position: r.maze.End}
+	if r.state == end {
+		err = errors.New("step: maze is already solved")
+		return
+	}
+	var actionIdx int
+	for i, x := range action {
+		if x != 0 {
+			actionIdx = i
+		}
+	}
+	if actionIdx >= ActionUp && actionIdx <= ActionLeft {
+		// Actions are in the same order as directions.
+		if next, ok := r.maze.Move(r.state, actionIdx-ActionUp); ok {
+			r.state = next
+		}
+	}
+	if r.state == end {
+		reward = 0
+		done = true
+	} else {
+		reward = -1
+	}
+	obs = r.observation()
+	return
+}
+
+func (r *rawWeaveEnv) observation() []float64 {
+	var res []float64
+	for _, pos := range r.maze.Positions() {
+		res = append(res, boolToFloat(pos == r.state.Position))
+		for dir := 0; dir < 4; dir++ {
+			res = append(res, boolToFloat(r.maze.Open(pos, dir)))
+		}
+		res = append(res, boolToFloat(r.maze.Tunnel(pos)),
+			boolToFloat(pos == r.maze.Start), boolToFloat(pos == r.maze.End))
+	}
+	return append(res, boolToFloat(r.state.Under))
+}
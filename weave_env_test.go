@@ -0,0 +1,67 @@
+package mazenv
+
+import "testing"
+
+func TestWeaveEnv(t *testing.T) {
+	env := NewWeaveEnv(testingWeaveMaze())
+
+	obs, err := env.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obs) != 9*8+1 {
+		t.Fatalf("unexpected observation size: %d", len(obs))
+	}
+	expectedCenter := []float64{0, 1, 0, 1, 0, 1, 0, 0}
+	testObsEqual(t, obs[4*8:5*8], expectedCenter)
+
+	for _, act := range []int{ActionNop, ActionUp, ActionLeft} {
+		_, reward, done, err := env.Step(oneHotAction(act))
+		testNotDoneStepResult(t, reward, done, err)
+	}
+
+	obs, reward, done, err := env.Step(oneHotAction(ActionRight))
+	testNotDoneStepResult(t, reward, done, err)
+	if s := env.State(); s.Position != (Position{1, 1}) || !s.Under {
+		t.Fatalf("unexpected state: %v", s)
+	}
+	if obs[len(obs)-1] != 1 || obs[4*8] != 1 {
+		t.Error("unexpected observation")
+	}
+
+	// Cannot surface from within the tunnel.
+	_, reward, done, err = env.Step(oneHotAction(ActionDown))
+	testNotDoneStepResult(t, reward, done, err)
+
+	obs, reward, done, err = env.Step(oneHotAction(ActionRight))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done || reward != 0 {
+		t.Error("expected episode to end with reward 0")
+	}
+	if obs[len(obs)-1] != 0 || obs[5*8] != 1 {
+		t.Error("unexpected observation")
+	}
+}
+
+func TestWeaveEnvUnknownAction(t *testing.T) {
+	env := NewWeaveEnv(testingWeaveMaze())
+	if _, err := env.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	_, reward, done, err := env.Step(oneHotAction(ActionRight))
+	testNotDoneStepResult(t, reward, done, err)
+	if s := env.State(); !s.Under {
+		t.Fatalf("expected to be in the tunnel: %v", s)
+	}
+
+	// Unknown actions are ignored, even in a tunnel.
+	action := make([]float64, 6)
+	action[5] = 1
+	_, reward, done, err = env.Step(action)
+	testNotDoneStepResult(t, reward, done, err)
+	if s := env.State(); s.Position != (Position{1, 1}) || !s.Under {
+		t.Errorf("unexpected state: %v", s)
+	}
+}
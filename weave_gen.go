@@ -0,0 +1,100 @@
+package mazenv
+
+import (
+	"errors"
+	"flag"
+	"math/rand"
+)
+
+// WeaveGenerator generates weave mazes using a randomized
+// depth-first search which may tunnel underneath straight
+// passages.
+type WeaveGenerator struct {
+	// Crossings is the probability of considering a tunnel
+	// underneath a neighboring cell when one is possible.
+	// The value may range from 0 to 1.
+	//
+	// If 0, a default of 0.5 is used.
+	Crossings float64
+}
+
+// Description returns a short description of what the
+// algorithm does.
+func (w *WeaveGenerator) Description() string {
+	return "depth-first search with passages crossing over and under"
+}
+
+// AddFlags adds the generator's options as flags.
+func (w *WeaveGenerator) AddFlags(fs *flag.FlagSet) {
+	fs.Float64Var(&w.Crossings, "crossings", 0.5, "chance of allowing a crossing")
+}
+
+// Generate generates a random maze.
+func (w *WeaveGenerator) Generate(rows, cols int) (*WeaveMaze, error) {
+	return w.GenerateRand(nil, rows, cols)
+}
+
+// GenerateRand is like Generate, but it draws random
+// numbers from rng.
+//
+// If rng is nil, the global source from math/rand is used.
+func (w *WeaveGenerator) GenerateRand(rng *rand.Rand, rows, cols int) (*WeaveMaze, error) {
+	rng = randOrGlobal(rng)
+	if rows*cols < 2 {
+		return nil, errors.New("not enough cells")
+	}
+	crossings := w.Crossings
+	if crossings == 0 {
+		crossings = 0.5
+	}
+
+	maze := NewWeaveMaze(rows, cols)
+	visited := make([]bool, rows*cols)
+	first := Position{Row: rng.Intn(rows), Col: rng.Intn(cols)}
+	visited[maze.CellIndex(first)] = true
+
+	type option struct {
+		Dir    int
+		Target Position
+		Tunnel bool
+	}
+	stack := []Position{first}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		var options []option
+		for dir := 0; dir < 4; dir++ {
+			next := addOffset(cur, weaveOffsets[dir])
+			if !maze.InBounds(next) {
+				continue
+			}
+			if !visited[maze.CellIndex(next)] {
+				options = append(options, option{Dir: dir, Target: next})
+				continue
+			}
+			beyond := addOffset(next, weaveOffsets[dir])
+			perpendicular := uint8(1<<uint((dir+1)%4) | 1<<uint((dir+3)%4))
+			if maze.InBounds(beyond) && !visited[maze.CellIndex(beyond)] &&
+				!maze.Tunnel(next) && maze.Passages[maze.CellIndex(next)] == perpendicular &&
+				rng.Float64() < crossings {
+				options = append(options, option{Dir: dir, Target: beyond, Tunnel: true})
+			}
+		}
+		if len(options) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		opt := options[rng.Intn(len(options))]
+		if opt.Tunnel {
+			maze.Tunnels[maze.CellIndex(addOffset(cur, weaveOffsets[opt.Dir]))] = true
+		}
+		maze.Passages[maze.CellIndex(cur)] |= 1 << uint(opt.Dir)
+		maze.Passages[maze.CellIndex(opt.Target)] |= 1 << uint((opt.Dir+2)%4)
+		visited[maze.CellIndex(opt.Target)] = true
+		stack = append(stack, opt.Target)
+	}
+
+	perm := rng.Perm(rows * cols)
+	maze.Start = Position{Row: perm[0] / cols, Col: perm[0] % cols}
+	maze.End = Position{Row: perm[1] / cols, Col: perm[1] % cols}
+	return maze, nil
+}
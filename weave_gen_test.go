@@ -0,0 +1,42 @@
+package mazenv
+
+import "testing"
+
+func TestWeaveGenerator(t *testing.T) {
+	gen := &WeaveGenerator{Crossings: 1}
+	var numTunnels int
+	for i := 0; i < 5; i++ {
+		m, err := gen.Generate(10, 12)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Start == m.End {
+			t.Error("overlapping start and end")
+		}
+
+		var numEdges int
+		for i, passages := range m.Passages {
+			for dir := uint(0); dir < 4; dir++ {
+				if passages&(1<<dir) != 0 {
+					numEdges++
+				}
+			}
+			if m.Tunnels[i] {
+				numTunnels++
+			}
+		}
+		if numEdges/2 != 10*12-1 {
+			t.Errorf("expected %d passages but got %d", 10*12-1, numEdges/2)
+		}
+
+		for _, p := range m.Positions() {
+			m.End = p
+			if p != m.Start && SolveWeave(m) == nil {
+				t.Fatalf("unreachable cell %v:\n%s", p, m.Render())
+			}
+		}
+	}
+	if numTunnels == 0 {
+		t.Error("no crossings were generated")
+	}
+}
@@ -0,0 +1,74 @@
+package mazenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWeaveMazeMove(t *testing.T) {
+	maze := testingWeaveMaze()
+
+	moves := []struct {
+		From WeaveState
+		Dir  int
+		To   WeaveState
+		OK   bool
+	}{
+		{WeaveState{Position{1, 0}, false}, WeaveEast, WeaveState{Position{1, 1}, true}, true},
+		{WeaveState{Position{1, 1}, true}, WeaveEast, WeaveState{Position{1, 2}, false}, true},
+		{WeaveState{Position{1, 1}, true}, WeaveWest, WeaveState{Position{1, 0}, false}, true},
+		{WeaveState{Position{1, 1}, true}, WeaveNorth, WeaveState{}, false},
+		{WeaveState{Position{0, 1}, false}, WeaveSouth, WeaveState{Position{1, 1}, false}, true},
+		{WeaveState{Position{1, 1}, false}, WeaveSouth, WeaveState{Position{2, 1}, false}, true},
+		{WeaveState{Position{1, 1}, false}, WeaveEast, WeaveState{}, false},
+		{WeaveState{Position{1, 0}, false}, WeaveNorth, WeaveState{}, false},
+		{WeaveState{Position{1, 1}, true}, 4, WeaveState{}, false},
+		{WeaveState{Position{1, 0}, false}, -1, WeaveState{}, false},
+	}
+	for _, m := range moves {
+		to, ok := maze.Move(m.From, m.Dir)
+		if ok != m.OK || (ok && to != m.To) {
+			t.Errorf("move %v from %v: expected %v %v but got %v %v",
+				m.Dir, m.From, m.To, m.OK, to, ok)
+		}
+	}
+}
+
+func TestWeaveMazeRender(t *testing.T) {
+	actual := testingWeaveMaze().Render()
+	expected := "·╷·\nA╂x\n·╵·"
+	if actual != expected {
+		t.Errorf("expected %#v but got %#v", expected, actual)
+	}
+}
+
+func TestSolveWeave(t *testing.T) {
+	maze := testingWeaveMaze()
+	actual := SolveWeave(maze)
+	expected := []WeaveState{
+		{Position{1, 0}, false},
+		{Position{1, 1}, true},
+		{Position{1, 2}, false},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+
+	maze.End = Position{2, 1}
+	if SolveWeave(maze) != nil {
+		t.Error("found false solution")
+	}
+}
+
+func testingWeaveMaze() *WeaveMaze {
+	maze := NewWeaveMaze(3, 3)
+	maze.Passages[maze.CellIndex(Position{0, 1})] = 1 << WeaveSouth
+	maze.Passages[maze.CellIndex(Position{1, 1})] = 1<<WeaveNorth | 1<<WeaveSouth
+	maze.Passages[maze.CellIndex(Position{2, 1})] = 1 << WeaveNorth
+	maze.Passages[maze.CellIndex(Position{1, 0})] = 1 << WeaveEast
+	maze.Passages[maze.CellIndex(Position{1, 2})] = 1 << WeaveWest
+	maze.Tunnels[maze.CellIndex(Position{1, 1})] = true
+	maze.Start = Position{1, 0}
+	maze.End = Position{1, 2}
+	return maze
+}